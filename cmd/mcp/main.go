@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "proto-to-mcp-tutorial/generated/go"
+)
+
+var (
+	grpcTarget = flag.String("grpc_target", "localhost:9090", "address of the BookstoreService gRPC server")
+	httpAddr   = flag.String("http", "", "serve streamable HTTP on this address instead of stdio, e.g. :8081")
+)
+
+func main() {
+	flag.Parse()
+
+	conn, err := grpc.NewClient(*grpcTarget, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect to %s: %v", *grpcTarget, err)
+	}
+	defer conn.Close()
+
+	server := pb.NewBookstoreServiceMCPServer(pb.NewBookstoreServiceClient(conn))
+
+	if *httpAddr == "" {
+		if err := server.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
+			log.Fatalf("failed to serve MCP over stdio: %v", err)
+		}
+		return
+	}
+
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	log.Printf("MCP server starting on %s", *httpAddr)
+	if err := http.ListenAndServe(*httpAddr, handler); err != nil {
+		log.Fatalf("failed to serve MCP over HTTP: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc"
+
+	pb "proto-to-mcp-tutorial/generated/go"
+)
+
+// fakeBookstoreClient answers without a gRPC server and records the book
+// passed to CreateBook.
+type fakeBookstoreClient struct {
+	created *pb.Book
+}
+
+func (c *fakeBookstoreClient) GetBook(ctx context.Context, in *pb.GetBookRequest, opts ...grpc.CallOption) (*pb.Book, error) {
+	return &pb.Book{BookId: in.GetBookId(), Title: "Dune"}, nil
+}
+
+func (c *fakeBookstoreClient) CreateBook(ctx context.Context, in *pb.CreateBookRequest, opts ...grpc.CallOption) (*pb.Book, error) {
+	c.created = in.GetBook()
+	return &pb.Book{BookId: "b1", Title: in.GetBook().GetTitle()}, nil
+}
+
+// connectBookstoreMCPServer serves the generated tools over an in-memory
+// transport and returns a connected client session.
+func connectBookstoreMCPServer(t *testing.T, client pb.BookstoreServiceClient) *mcp.ClientSession {
+	t.Helper()
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := pb.NewBookstoreServiceMCPServer(client).Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server Connect failed: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() })
+
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client Connect failed: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestBookstoreMCPToolHints(t *testing.T) {
+	session := connectBookstoreMCPServer(t, &fakeBookstoreClient{})
+
+	result, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+
+	readOnly := map[string]bool{}
+	for _, tool := range result.Tools {
+		readOnly[tool.Name] = tool.Annotations != nil && tool.Annotations.ReadOnlyHint
+	}
+	want := map[string]bool{"get_book": true, "create_book": false}
+	for name, hint := range want {
+		if got, ok := readOnly[name]; !ok || got != hint {
+			t.Errorf("tool %s: readOnlyHint = %t (listed %t), want %t", name, got, ok, hint)
+		}
+	}
+}
+
+func TestBookstoreMCPToolArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		tool      string
+		arguments map[string]any
+		err       string // Expected error text, empty for a successful call
+	}{
+		{name: "valid", tool: "create_book", arguments: map[string]any{"book": map[string]any{"title": "Dune", "author": "Herbert", "pages": 412}}},
+		{name: "output-only field", tool: "create_book", arguments: map[string]any{"book": map[string]any{"book_id": "b1", "title": "Dune", "author": "Herbert", "pages": 412}}, err: "book_id"},
+		{name: "missing required field", tool: "get_book", arguments: map[string]any{}, err: "book_id"},
+		{name: "unknown argument", tool: "get_book", arguments: map[string]any{"book_id": "b1", "isbn": "x"}, err: "isbn"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &fakeBookstoreClient{}
+			session := connectBookstoreMCPServer(t, client)
+
+			result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: test.tool, Arguments: test.arguments})
+			if err != nil {
+				t.Fatalf("CallTool failed: %v", err)
+			}
+			text := result.Content[0].(*mcp.TextContent).Text
+
+			if test.err == "" {
+				if result.IsError {
+					t.Fatalf("CallTool returned a tool error: %s", text)
+				}
+				return
+			}
+			if !result.IsError || !strings.Contains(text, test.err) {
+				t.Errorf("CallTool result = %q (error %t), want an error mentioning %q", text, result.IsError, test.err)
+			}
+			if client.created != nil {
+				t.Errorf("rejected arguments reached the RPC as %v", client.created)
+			}
+		})
+	}
+}
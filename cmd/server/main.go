@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net"
@@ -11,10 +12,15 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/encoding/protojson"
 
 	pb "proto-to-mcp-tutorial/generated/go"
 )
 
+// protoNames must match the name_style the MCP server was generated with:
+// true for "proto" (book_id), false for "json" (bookId).
+var protoNames = flag.Bool("proto_names", true, "use proto field names instead of lowerCamelCase JSON names in REST payloads")
+
 type server struct {
 	pb.UnimplementedBookstoreServiceServer
 	books map[string]*pb.Book
@@ -51,6 +57,8 @@ func (s *server) CreateBook(ctx context.Context, req *pb.CreateBookRequest) (*pb
 }
 
 func main() {
+	flag.Parse()
+
 	// Initialize server with some sample data
 	srv := &server{
 		books: map[string]*pb.Book{
@@ -83,7 +91,19 @@ func main() {
 
 	// Start REST gateway
 	ctx := context.Background()
-	mux := runtime.NewServeMux()
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			// Only UseProtoNames departs from the gateway's default marshaler.
+			// Requests accept both proto and JSON field names either way.
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   *protoNames,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+	)
 
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 	err := pb.RegisterBookstoreServiceHandlerFromEndpoint(ctx, mux, "localhost:9090", opts)
@@ -58,6 +58,10 @@ protoc -I${GOOGLEAPIS_DIR} -I${MCP_DIR} -I./proto --openapi_out=./generated/open
       --openapi_opt=title="Bookstore API" \
       bookstore.proto
 
+# Build the MCP plugin so it matches the sources
+echo "🔧 Building protoc-gen-mcp..."
+go build -o ./protoc-gen-mcp ./plugins/protoc-gen-mcp
+
 # Generate MCP server (always available since we have our custom plugin)
 echo "🔧 Generating MCP server..."
 protoc -I${GOOGLEAPIS_DIR} -I${MCP_DIR} --proto_path=proto \
@@ -65,6 +69,30 @@ protoc -I${GOOGLEAPIS_DIR} -I${MCP_DIR} --proto_path=proto \
       --mcp_out=./generated/mcp \
       bookstore.proto
 
+# Generate the Go MCP tools next to the gRPC stubs
+echo "🔧 Generating Go MCP server..."
+protoc -I${GOOGLEAPIS_DIR} -I${MCP_DIR} --proto_path=proto \
+      --plugin=protoc-gen-mcp=./protoc-gen-mcp \
+      --mcp_out=./generated/go \
+      --mcp_opt=paths=source_relative,target=go \
+      bookstore.proto
+
+# Generate the TypeScript MCP server
+echo "🔧 Generating TypeScript MCP server..."
+protoc -I${GOOGLEAPIS_DIR} -I${MCP_DIR} --proto_path=proto \
+      --plugin=protoc-gen-mcp=./protoc-gen-mcp \
+      --mcp_out=./generated/typescript \
+      --mcp_opt=target=typescript \
+      bookstore.proto
+
+# Generate the language-neutral tool manifest
+echo "🔧 Generating MCP tool manifest..."
+protoc -I${GOOGLEAPIS_DIR} -I${MCP_DIR} --proto_path=proto \
+      --plugin=protoc-gen-mcp=./protoc-gen-mcp \
+      --mcp_out=./generated/mcp \
+      --mcp_opt=target=manifest \
+      bookstore.proto
+
 echo ""
 echo "🎉 Generation complete!"
 echo "📁 Check the ./generated directory for all generated files."
\ No newline at end of file
@@ -36,17 +36,6 @@ type BookstoreServiceClient interface {
 	//     - If the user has not provided a value , prompt the user to supply it (otherwise the request will fail).
 	//  2. For optional fields:
 	//     - If not set by the user, do not set the field in the request and omit them.
-	//
-	// Example payload for creating a book:
-	//
-	//	{
-	//	  "book": {
-	//	    "bookId": "string", // optional
-	//	    "title": "string", // required
-	//	    "author": "string", // required
-	//	    "pages": int // required
-	//	  }
-	//	}
 	CreateBook(ctx context.Context, in *CreateBookRequest, opts ...grpc.CallOption) (*Book, error)
 }
 
@@ -89,17 +78,6 @@ type BookstoreServiceServer interface {
 	//     - If the user has not provided a value , prompt the user to supply it (otherwise the request will fail).
 	//  2. For optional fields:
 	//     - If not set by the user, do not set the field in the request and omit them.
-	//
-	// Example payload for creating a book:
-	//
-	//	{
-	//	  "book": {
-	//	    "bookId": "string", // optional
-	//	    "title": "string", // required
-	//	    "author": "string", // required
-	//	    "pages": int // required
-	//	  }
-	//	}
 	CreateBook(context.Context, *CreateBookRequest) (*Book, error)
 	mustEmbedUnimplementedBookstoreServiceServer()
 }
@@ -21,14 +21,87 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Backends a generated tool can call
+type MCPBackend int32
+
+const (
+	// Use the plugin's backend option
+	MCPBackend_MCP_BACKEND_UNSPECIFIED MCPBackend = 0
+	// Call the REST endpoint from the method's google.api.http rule
+	MCPBackend_MCP_BACKEND_HTTP MCPBackend = 1
+	// Call the gRPC method directly, which needs no google.api.http rule
+	MCPBackend_MCP_BACKEND_GRPC MCPBackend = 2
+)
+
+// Enum value maps for MCPBackend.
+var (
+	MCPBackend_name = map[int32]string{
+		0: "MCP_BACKEND_UNSPECIFIED",
+		1: "MCP_BACKEND_HTTP",
+		2: "MCP_BACKEND_GRPC",
+	}
+	MCPBackend_value = map[string]int32{
+		"MCP_BACKEND_UNSPECIFIED": 0,
+		"MCP_BACKEND_HTTP":        1,
+		"MCP_BACKEND_GRPC":        2,
+	}
+)
+
+func (x MCPBackend) Enum() *MCPBackend {
+	p := new(MCPBackend)
+	*p = x
+	return p
+}
+
+func (x MCPBackend) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MCPBackend) Descriptor() protoreflect.EnumDescriptor {
+	return file_mcp_protobuf_annotations_proto_enumTypes[0].Descriptor()
+}
+
+func (MCPBackend) Type() protoreflect.EnumType {
+	return &file_mcp_protobuf_annotations_proto_enumTypes[0]
+}
+
+func (x MCPBackend) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MCPBackend.Descriptor instead.
+func (MCPBackend) EnumDescriptor() ([]byte, []int) {
+	return file_mcp_protobuf_annotations_proto_rawDescGZIP(), []int{0}
+}
+
 // MCP tool configuration options
 type MCPToolOptions struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Whether this method should be exposed as an MCP tool
-	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Whether this method should be exposed as an MCP tool. When unset, the
+	// service's expose_all option decides, so other tool options can be set
+	// without repeating enabled: true.
+	Enabled *bool `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	// Tool name exposed to MCP clients. Defaults to the snake_cased RPC name.
+	// Names may use ASCII letters, digits, "_", "-" and ".", up to 128 of them.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Human-readable title shown by MCP hosts.
+	Title string `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	// Tool description for agents. Defaults to the method's leading comment.
+	Description string `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// The tool does not modify its environment. Defaults to true for GET.
+	ReadOnlyHint *bool `protobuf:"varint,5,opt,name=read_only_hint,json=readOnlyHint,proto3,oneof" json:"read_only_hint,omitempty"`
+	// The tool may perform destructive updates. Defaults to true for DELETE.
+	DestructiveHint *bool `protobuf:"varint,6,opt,name=destructive_hint,json=destructiveHint,proto3,oneof" json:"destructive_hint,omitempty"`
+	// Repeated calls with the same arguments have no additional effect.
+	// Defaults to true for PUT.
+	IdempotentHint *bool `protobuf:"varint,7,opt,name=idempotent_hint,json=idempotentHint,proto3,oneof" json:"idempotent_hint,omitempty"`
+	// The tool interacts with an open world of external entities.
+	OpenWorldHint *bool `protobuf:"varint,8,opt,name=open_world_hint,json=openWorldHint,proto3,oneof" json:"open_world_hint,omitempty"`
+	// How the tool reaches the service. Defaults to the plugin's backend option.
+	Backend MCPBackend `protobuf:"varint,9,opt,name=backend,proto3,enum=mcp.v1.MCPBackend" json:"backend,omitempty"`
 }
 
 func (x *MCPToolOptions) Reset() {
@@ -64,12 +137,296 @@ func (*MCPToolOptions) Descriptor() ([]byte, []int) {
 }
 
 func (x *MCPToolOptions) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *MCPToolOptions) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MCPToolOptions) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MCPToolOptions) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *MCPToolOptions) GetReadOnlyHint() bool {
+	if x != nil && x.ReadOnlyHint != nil {
+		return *x.ReadOnlyHint
+	}
+	return false
+}
+
+func (x *MCPToolOptions) GetDestructiveHint() bool {
+	if x != nil && x.DestructiveHint != nil {
+		return *x.DestructiveHint
+	}
+	return false
+}
+
+func (x *MCPToolOptions) GetIdempotentHint() bool {
+	if x != nil && x.IdempotentHint != nil {
+		return *x.IdempotentHint
+	}
+	return false
+}
+
+func (x *MCPToolOptions) GetOpenWorldHint() bool {
+	if x != nil && x.OpenWorldHint != nil {
+		return *x.OpenWorldHint
+	}
+	return false
+}
+
+func (x *MCPToolOptions) GetBackend() MCPBackend {
+	if x != nil {
+		return x.Backend
+	}
+	return MCPBackend_MCP_BACKEND_UNSPECIFIED
+}
+
+// MCP field configuration options
+type MCPFieldOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Hide the field from tool inputs, e.g. for server-assigned IDs
+	Hidden bool `protobuf:"varint,1,opt,name=hidden,proto3" json:"hidden,omitempty"`
+	// Field description for agents. Defaults to the field's leading comment.
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Example values, written as they would appear in the JSON payload, such
+	// as "\"abc\"" or "42". Bare text is accepted for string fields.
+	Examples []string `protobuf:"bytes,3,rep,name=examples,proto3" json:"examples,omitempty"`
+	// Force the field required (true) or optional (false). Without it, a
+	// google.api.field_behavior of REQUIRED makes the field required and
+	// OPTIONAL or OUTPUT_ONLY make it optional. Otherwise scalar and enum
+	// fields are required, while oneof members, message, repeated and map
+	// fields and fields using the proto3 optional keyword are optional.
+	Required *bool `protobuf:"varint,4,opt,name=required,proto3,oneof" json:"required,omitempty"`
+}
+
+func (x *MCPFieldOptions) Reset() {
+	*x = MCPFieldOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mcp_protobuf_annotations_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MCPFieldOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MCPFieldOptions) ProtoMessage() {}
+
+func (x *MCPFieldOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_protobuf_annotations_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MCPFieldOptions.ProtoReflect.Descriptor instead.
+func (*MCPFieldOptions) Descriptor() ([]byte, []int) {
+	return file_mcp_protobuf_annotations_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MCPFieldOptions) GetHidden() bool {
+	if x != nil {
+		return x.Hidden
+	}
+	return false
+}
+
+func (x *MCPFieldOptions) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *MCPFieldOptions) GetExamples() []string {
 	if x != nil {
-		return x.Enabled
+		return x.Examples
+	}
+	return nil
+}
+
+func (x *MCPFieldOptions) GetRequired() bool {
+	if x != nil && x.Required != nil {
+		return *x.Required
 	}
 	return false
 }
 
+// MCP service configuration options
+type MCPServiceOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expose every method of the service as an MCP tool. Methods can still opt
+	// out with option (mcp.v1.tool) = { enabled: false }.
+	ExposeAll bool `protobuf:"varint,1,opt,name=expose_all,json=exposeAll,proto3" json:"expose_all,omitempty"`
+	// Prefix prepended to every tool name of the service, e.g. "bookstore_"
+	ToolPrefix string `protobuf:"bytes,2,opt,name=tool_prefix,json=toolPrefix,proto3" json:"tool_prefix,omitempty"`
+	// Absolute base URL of the HTTP backend for this service, such as
+	// https://books.example.com. Defaults to API_BASE.
+	BaseUrl string `protobuf:"bytes,3,opt,name=base_url,json=baseUrl,proto3" json:"base_url,omitempty"`
+	// Address of the gRPC backend for this service. Defaults to GRPC_TARGET.
+	GrpcTarget string `protobuf:"bytes,4,opt,name=grpc_target,json=grpcTarget,proto3" json:"grpc_target,omitempty"`
+}
+
+func (x *MCPServiceOptions) Reset() {
+	*x = MCPServiceOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mcp_protobuf_annotations_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MCPServiceOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MCPServiceOptions) ProtoMessage() {}
+
+func (x *MCPServiceOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_protobuf_annotations_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MCPServiceOptions.ProtoReflect.Descriptor instead.
+func (*MCPServiceOptions) Descriptor() ([]byte, []int) {
+	return file_mcp_protobuf_annotations_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MCPServiceOptions) GetExposeAll() bool {
+	if x != nil {
+		return x.ExposeAll
+	}
+	return false
+}
+
+func (x *MCPServiceOptions) GetToolPrefix() string {
+	if x != nil {
+		return x.ToolPrefix
+	}
+	return ""
+}
+
+func (x *MCPServiceOptions) GetBaseUrl() string {
+	if x != nil {
+		return x.BaseUrl
+	}
+	return ""
+}
+
+func (x *MCPServiceOptions) GetGrpcTarget() string {
+	if x != nil {
+		return x.GrpcTarget
+	}
+	return ""
+}
+
+// MCP server configuration options
+type MCPServerOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Server name reported to MCP clients. Defaults to the proto package.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Server version reported to MCP clients. Defaults to "0.0.0" for every
+	// target.
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Instructions sent to MCP clients on initialization, describing what the
+	// server is for and how its tools fit together
+	Instructions string `protobuf:"bytes,3,opt,name=instructions,proto3" json:"instructions,omitempty"`
+}
+
+func (x *MCPServerOptions) Reset() {
+	*x = MCPServerOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mcp_protobuf_annotations_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MCPServerOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MCPServerOptions) ProtoMessage() {}
+
+func (x *MCPServerOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_protobuf_annotations_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MCPServerOptions.ProtoReflect.Descriptor instead.
+func (*MCPServerOptions) Descriptor() ([]byte, []int) {
+	return file_mcp_protobuf_annotations_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MCPServerOptions) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MCPServerOptions) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *MCPServerOptions) GetInstructions() string {
+	if x != nil {
+		return x.Instructions
+	}
+	return ""
+}
+
 var file_mcp_protobuf_annotations_proto_extTypes = []protoimpl.ExtensionInfo{
 	{
 		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
@@ -79,6 +436,30 @@ var file_mcp_protobuf_annotations_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "bytes,50003,opt,name=tool",
 		Filename:      "mcp/protobuf/annotations.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*MCPFieldOptions)(nil),
+		Field:         50004,
+		Name:          "mcp.v1.field",
+		Tag:           "bytes,50004,opt,name=field",
+		Filename:      "mcp/protobuf/annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*MCPServerOptions)(nil),
+		Field:         50006,
+		Name:          "mcp.v1.server",
+		Tag:           "bytes,50006,opt,name=server",
+		Filename:      "mcp/protobuf/annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*MCPServiceOptions)(nil),
+		Field:         50005,
+		Name:          "mcp.v1.service",
+		Tag:           "bytes,50005,opt,name=service",
+		Filename:      "mcp/protobuf/annotations.proto",
+	},
 }
 
 // Extension fields to descriptorpb.MethodOptions.
@@ -87,6 +468,24 @@ var (
 	E_Tool = &file_mcp_protobuf_annotations_proto_extTypes[0]
 )
 
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// optional mcp.v1.MCPFieldOptions field = 50004;
+	E_Field = &file_mcp_protobuf_annotations_proto_extTypes[1]
+)
+
+// Extension fields to descriptorpb.FileOptions.
+var (
+	// optional mcp.v1.MCPServerOptions server = 50006;
+	E_Server = &file_mcp_protobuf_annotations_proto_extTypes[2]
+)
+
+// Extension fields to descriptorpb.ServiceOptions.
+var (
+	// optional mcp.v1.MCPServiceOptions service = 50005;
+	E_Service = &file_mcp_protobuf_annotations_proto_extTypes[3]
+)
+
 var File_mcp_protobuf_annotations_proto protoreflect.FileDescriptor
 
 var file_mcp_protobuf_annotations_proto_rawDesc = []byte{
@@ -94,18 +493,90 @@ var file_mcp_protobuf_annotations_proto_rawDesc = []byte{
 	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x12, 0x06, 0x6d, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2a, 0x0a, 0x0e, 0x4d, 0x43,
-	0x50, 0x54, 0x6f, 0x6f, 0x6c, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x18, 0x0a, 0x07,
-	0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65,
-	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x3a, 0x4c, 0x0a, 0x04, 0x74, 0x6f, 0x6f, 0x6c, 0x12, 0x1e,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd3,
-	0x86, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e,
-	0x4d, 0x43, 0x50, 0x54, 0x6f, 0x6f, 0x6c, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x04,
-	0x74, 0x6f, 0x6f, 0x6c, 0x42, 0x31, 0x5a, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2d, 0x74, 0x6f,
-	0x2d, 0x6d, 0x63, 0x70, 0x2d, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x2f, 0x67, 0x65,
-	0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2f, 0x67, 0x6f, 0x2f, 0x6d, 0x63, 0x70, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbb, 0x03, 0x0a, 0x0e, 0x4d,
+	0x43, 0x50, 0x54, 0x6f, 0x6f, 0x6c, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1d, 0x0a,
+	0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00,
+	0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x0e, 0x72, 0x65, 0x61, 0x64,
+	0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x01, 0x52, 0x0c, 0x72, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x48, 0x69, 0x6e, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x10, 0x64, 0x65, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52,
+	0x0f, 0x64, 0x65, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x76, 0x65, 0x48, 0x69, 0x6e, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e,
+	0x74, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x0e,
+	0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x74, 0x48, 0x69, 0x6e, 0x74, 0x88, 0x01,
+	0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x6f, 0x70, 0x65, 0x6e, 0x5f, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x5f,
+	0x68, 0x69, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x0d, 0x6f, 0x70,
+	0x65, 0x6e, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x48, 0x69, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2c,
+	0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x12, 0x2e, 0x6d, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x43, 0x50, 0x42, 0x61, 0x63, 0x6b,
+	0x65, 0x6e, 0x64, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x72, 0x65, 0x61,
+	0x64, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x42, 0x13, 0x0a, 0x11, 0x5f,
+	0x64, 0x65, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x68, 0x69, 0x6e, 0x74,
+	0x42, 0x12, 0x0a, 0x10, 0x5f, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x74, 0x5f,
+	0x68, 0x69, 0x6e, 0x74, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x5f, 0x77, 0x6f,
+	0x72, 0x6c, 0x64, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x22, 0x95, 0x01, 0x0a, 0x0f, 0x4d, 0x43, 0x50,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x68, 0x69,
+	0x64, 0x64, 0x65, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x73, 0x12, 0x1f, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64,
+	0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64,
+	0x22, 0x8f, 0x01, 0x0a, 0x11, 0x4d, 0x43, 0x50, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x6f, 0x73, 0x65,
+	0x5f, 0x61, 0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x65, 0x78, 0x70, 0x6f,
+	0x73, 0x65, 0x41, 0x6c, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x6f, 0x6c, 0x5f, 0x70, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x6f, 0x6f, 0x6c,
+	0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x75,
+	0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x73, 0x65, 0x55, 0x72,
+	0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x67, 0x72, 0x70, 0x63, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x22, 0x64, 0x0a, 0x10, 0x4d, 0x43, 0x50, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2a, 0x55, 0x0a, 0x0a, 0x4d, 0x43, 0x50, 0x42,
+	0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x1b, 0x0a, 0x17, 0x4d, 0x43, 0x50, 0x5f, 0x42, 0x41,
+	0x43, 0x4b, 0x45, 0x4e, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x43, 0x50, 0x5f, 0x42, 0x41, 0x43, 0x4b, 0x45,
+	0x4e, 0x44, 0x5f, 0x48, 0x54, 0x54, 0x50, 0x10, 0x01, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x43, 0x50,
+	0x5f, 0x42, 0x41, 0x43, 0x4b, 0x45, 0x4e, 0x44, 0x5f, 0x47, 0x52, 0x50, 0x43, 0x10, 0x02, 0x3a,
+	0x4c, 0x0a, 0x04, 0x74, 0x6f, 0x6f, 0x6c, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd3, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x6d, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x43, 0x50, 0x54, 0x6f, 0x6f, 0x6c,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x04, 0x74, 0x6f, 0x6f, 0x6c, 0x3a, 0x4e, 0x0a,
+	0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd4, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x6d, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x43, 0x50, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x3a, 0x50, 0x0a,
+	0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd6, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x6d, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x43, 0x50, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x3a,
+	0x56, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd5, 0x86, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x43, 0x50,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x42, 0x31, 0x5a, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2d, 0x74, 0x6f, 0x2d, 0x6d, 0x63, 0x70, 0x2d, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c,
+	0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2f, 0x67, 0x6f, 0x2f, 0x6d, 0x63,
+	0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -120,19 +591,34 @@ func file_mcp_protobuf_annotations_proto_rawDescGZIP() []byte {
 	return file_mcp_protobuf_annotations_proto_rawDescData
 }
 
-var file_mcp_protobuf_annotations_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_mcp_protobuf_annotations_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_mcp_protobuf_annotations_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_mcp_protobuf_annotations_proto_goTypes = []interface{}{
-	(*MCPToolOptions)(nil),             // 0: mcp.v1.MCPToolOptions
-	(*descriptorpb.MethodOptions)(nil), // 1: google.protobuf.MethodOptions
+	(MCPBackend)(0),                     // 0: mcp.v1.MCPBackend
+	(*MCPToolOptions)(nil),              // 1: mcp.v1.MCPToolOptions
+	(*MCPFieldOptions)(nil),             // 2: mcp.v1.MCPFieldOptions
+	(*MCPServiceOptions)(nil),           // 3: mcp.v1.MCPServiceOptions
+	(*MCPServerOptions)(nil),            // 4: mcp.v1.MCPServerOptions
+	(*descriptorpb.MethodOptions)(nil),  // 5: google.protobuf.MethodOptions
+	(*descriptorpb.FieldOptions)(nil),   // 6: google.protobuf.FieldOptions
+	(*descriptorpb.FileOptions)(nil),    // 7: google.protobuf.FileOptions
+	(*descriptorpb.ServiceOptions)(nil), // 8: google.protobuf.ServiceOptions
 }
 var file_mcp_protobuf_annotations_proto_depIdxs = []int32{
-	1, // 0: mcp.v1.tool:extendee -> google.protobuf.MethodOptions
-	0, // 1: mcp.v1.tool:type_name -> mcp.v1.MCPToolOptions
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	1, // [1:2] is the sub-list for extension type_name
-	0, // [0:1] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: mcp.v1.MCPToolOptions.backend:type_name -> mcp.v1.MCPBackend
+	5, // 1: mcp.v1.tool:extendee -> google.protobuf.MethodOptions
+	6, // 2: mcp.v1.field:extendee -> google.protobuf.FieldOptions
+	7, // 3: mcp.v1.server:extendee -> google.protobuf.FileOptions
+	8, // 4: mcp.v1.service:extendee -> google.protobuf.ServiceOptions
+	1, // 5: mcp.v1.tool:type_name -> mcp.v1.MCPToolOptions
+	2, // 6: mcp.v1.field:type_name -> mcp.v1.MCPFieldOptions
+	4, // 7: mcp.v1.server:type_name -> mcp.v1.MCPServerOptions
+	3, // 8: mcp.v1.service:type_name -> mcp.v1.MCPServiceOptions
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	5, // [5:9] is the sub-list for extension type_name
+	1, // [1:5] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_mcp_protobuf_annotations_proto_init() }
@@ -153,19 +639,58 @@ func file_mcp_protobuf_annotations_proto_init() {
 				return nil
 			}
 		}
+		file_mcp_protobuf_annotations_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MCPFieldOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mcp_protobuf_annotations_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MCPServiceOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mcp_protobuf_annotations_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MCPServerOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
+	file_mcp_protobuf_annotations_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	file_mcp_protobuf_annotations_proto_msgTypes[1].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_mcp_protobuf_annotations_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   1,
-			NumExtensions: 1,
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 4,
 			NumServices:   0,
 		},
 		GoTypes:           file_mcp_protobuf_annotations_proto_goTypes,
 		DependencyIndexes: file_mcp_protobuf_annotations_proto_depIdxs,
+		EnumInfos:         file_mcp_protobuf_annotations_proto_enumTypes,
 		MessageInfos:      file_mcp_protobuf_annotations_proto_msgTypes,
 		ExtensionInfos:    file_mcp_protobuf_annotations_proto_extTypes,
 	}.Build()
@@ -1,49 +1,56 @@
 #!/usr/bin/env python3
 """
-MCP Server for UPM API - Auto-generated from Protocol Buffers
+Bookstore Server - MCP server auto-generated from Protocol Buffers
 
-This server provides access to project management operations
-through the Model Context Protocol.
+Look up books in the bookstore catalog and add new ones.
 """
 
 import os
 import sys
-from typing import Any
+from typing import Annotated, Any, Literal, Optional, Union
 import json
+import re
+from urllib.parse import quote
 
 import httpx
 from mcp.server.fastmcp import FastMCP
+from mcp.types import ToolAnnotations
+from pydantic import Field
+from typing_extensions import Required, TypedDict
 
-API_BASE = 'http://localhost:8080'
+API_BASE = "http://localhost:8080"
 VERIFY_SSL = False
 
 # Initialize FastMCP
-mcp = FastMCP('Bookstore Server')
+mcp = FastMCP("Bookstore Server", instructions="Look up books in the bookstore catalog and add new ones.")
 
-async def make_api_request(url: str, method: str = "GET", payload: dict = None) -> dict[str, Any] | None:
-    """Make a HTTP request to the specified URL."""
+# FastMCP takes no version argument, so set it on the underlying low-level
+# server when this SDK release still exposes it
+if hasattr(mcp, "_mcp_server"):
+    mcp._mcp_server.version = "1.0.0"
+
+async def make_api_request(url: str, method: str = "GET", payload: Any = None, params: list = None, response_body: str = None) -> dict[str, Any] | None:
+    """Make a HTTP request to the specified URL.
+
+    When the HTTP rule sets response_body, the API returns that field alone and
+    it is wrapped back under its key.
+    """
 
     headers = {
         "Content-Type": "application/json",
     }
-    # Use SSL verification based on environment variable
+    # Verify TLS certificates as set by the verify_tls plugin option
     async with httpx.AsyncClient(verify=VERIFY_SSL) as client:
         try:
-            if method.upper() == "GET":
-                response = await client.get(url, headers=headers, timeout=30.0)
-            elif method.upper() == "PUT":
-                response = await client.put(url, headers=headers, json=payload, timeout=30.0)
-            elif method.upper() == "POST":
-                response = await client.post(url, headers=headers, json=payload, timeout=30.0)
-            elif method.upper() == "DELETE":
-                response = await client.delete(url, headers=headers, timeout=30.0)
-            elif method.upper() == "PATCH":
-                response = await client.patch(url, headers=headers, json=payload, timeout=30.0)
-            else:
-                return {"error": f"Unsupported HTTP method: {method}"}
+            # Any verb, including custom HttpRule kinds, goes through request()
+            response = await client.request(method, url, headers=headers, params=params, json=payload, timeout=30.0)
             
             response.raise_for_status()
             
+            # HEAD and OPTIONS answer with headers rather than a body
+            if method.upper() in ("HEAD", "OPTIONS") and not response.content:
+                return {"status_code": response.status_code, "headers": dict(response.headers)}
+            
             # Handle DELETE responses that might be empty
             if method.upper() == "DELETE":
                 if response.status_code == 200 or response.status_code == 204:
@@ -51,20 +58,93 @@ async def make_api_request(url: str, method: str = "GET", payload: dict = None)
                 
             # Try to parse JSON, return empty dict if no content
             try:
-                return response.json()
+                result = response.json()
             except:
                 return {"success": True}
+            if response_body:
+                return {response_body: result}
+            return result
                 
         except httpx.HTTPStatusError as e:
             return {"error": f"HTTP {e.response.status_code}: {e.response.text}"}
         except Exception as e:
             return {"error": str(e)}
 
+def find_oneof_conflicts(arguments: dict, groups: list) -> list[str]:
+    """Describe oneof groups that have more than one member set."""
+    conflicts = []
+    for path, name, members in groups:
+        value = arguments
+        for key in path:
+            value = value.get(key) if isinstance(value, dict) else None
+        if not isinstance(value, dict):
+            continue
+        present = [member for member in members if value.get(member) is not None]
+        if len(present) > 1:
+            prefix = ".".join(path + [""])
+            conflicts.append(f"Only one of {', '.join(prefix + m for m in members)} may be set for oneof '{name}', got {', '.join(prefix + m for m in present)}")
+    return conflicts
+
+def path_value(name: str, value: Any, pattern: Optional[str] = None, multi_segment: bool = False) -> str:
+    """Expand a path template variable.
+
+    Single-segment variables percent-escape every reserved character
+    including "/", multi-segment ones keep "/" as the separator.
+    """
+    if value is None:
+        raise ValueError(f"missing value for path parameter {name}")
+    if isinstance(value, bool):
+        value = "true" if value else "false"
+    value = str(value)
+    if pattern and not re.fullmatch(pattern, value):
+        raise ValueError(f"{name} must match the pattern {pattern}, got {value!r}")
+    return quote(value, safe="/" if multi_segment else "")
+
+def nested_value(value: Any, keys: list) -> Any:
+    """Read a nested field of a message argument, None if any level is unset."""
+    for key in keys:
+        if not isinstance(value, dict):
+            return None
+        value = value.get(key)
+    return value
+
+def add_query_param(params: list, name: str, value: Any, maps: list = (), path: str = "") -> None:
+    """Encode a value as grpc-gateway query parameters.
+
+    Nested messages become dotted paths (a.b=c), repeated fields repeat the
+    key and booleans use their JSON spelling. Map fields, listed in maps by
+    their path below the argument ("" for the argument itself), become
+    a[key]=value.
+    """
+    if value is None:
+        return
+    if isinstance(value, dict) and path in maps:
+        for key, item in value.items():
+            add_query_param(params, f"{name}[{key}]", item)
+    elif isinstance(value, dict):
+        for key, item in value.items():
+            add_query_param(params, f"{name}.{key}", item, maps, f"{path}.{key}" if path else key)
+    elif isinstance(value, list):
+        for item in value:
+            add_query_param(params, name, item, maps, path)
+    elif isinstance(value, bool):
+        params.append((name, "true" if value else "false"))
+    else:
+        params.append((name, str(value)))
+
+# Message types
+
+BookType = TypedDict("Book", {
+    "title": Required[str],
+    "author": Required[str],
+    "pages": Required[Annotated[int, Field(ge=-2147483648, le=2147483647)]],
+}, total=False)
+
 # MCP Tools
 
 
-@mcp.tool()
-async def get_book(book_id: str) -> str:
+@mcp.tool(name="get_book", annotations=ToolAnnotations(readOnlyHint=True))
+async def get_book(*, book_id: Annotated[str, Field(description="The ID of the book to retrieve")]) -> str:
     """Get a book by ID
     
     HTTP: GET /v1/books/{book_id}
@@ -74,39 +154,42 @@ async def get_book(book_id: str) -> str:
     
     Returns:
     - str: JSON formatted response from the API containing the result or error information
+      - book_id (string, read-only): Assigned by the server when the book is created
+      - title (string)
+      - author (string)
+      - pages (integer)
     """
     try:
         
         # Construct the URL
-        url = f"{API_BASE}/v1/books/{book_id}"
-        
-        url = url.replace("{" + "book_id" + "}", str(book_id))
-        
-        # Prepare payload for non-GET requests
-        payload = {}
-        
-        
+        _url = API_BASE + "/v1/books/" + path_value("book_id", book_id, None, False)
+
+        # Prepare the request body
+        _payload = None
+
+        # Encode the remaining fields as query parameters
+        _params = []
+
         # Make the API request
-        result = await make_api_request(url, "GET", payload if payload else None)
+        _result = await make_api_request(_url, "GET", _payload if _payload else None, _params)
         
         
         # Return formatted JSON response
-        import json
-        return json.dumps(result, indent=2)
+        return json.dumps(_result, indent=2)
         
     except Exception as e:
-        # Handle any errors that occur during execution
-        import json
+        # Handle any errors that occur during execution, without builtins
+        # such as type() that an argument of the same name would shadow
         error_result = {
-            "error": f"Tool execution failed: {str(e)}",
+            "error": f"Tool execution failed: {e}",
             "tool_name": "get_book",
-            "error_type": type(e).__name__
+            "error_type": e.__class__.__name__
         }
         return json.dumps(error_result, indent=2)
 
 
-@mcp.tool()
-async def create_book(book: dict) -> str:
+@mcp.tool(name="create_book")
+async def create_book(*, book: Annotated["BookType", Field(description="The book object to create.")]) -> str:
     """Create a new book in the system.
 
  INSTRUCTIONS:
@@ -114,51 +197,48 @@ async def create_book(book: dict) -> str:
       - If the user has not provided a value , prompt the user to supply it (otherwise the request will fail).
    2. For optional fields:
       - If not set by the user, do not set the field in the request and omit them.
-
- Example payload for creating a book:
- {
-   "book": {
-     "bookId": "string", // optional
-     "title": "string", // required
-     "author": "string", // required
-     "pages": int // required
-   }
- }
     
     HTTP: POST /v1/books
     
     Parameters:
     - book (object): The book object to create.
+      - title (string)
+      - author (string)
+      - pages (integer)
     
     Returns:
     - str: JSON formatted response from the API containing the result or error information
+      - book_id (string, read-only): Assigned by the server when the book is created
+      - title (string)
+      - author (string)
+      - pages (integer)
     """
     try:
         
         # Construct the URL
-        url = f"{API_BASE}/v1/books"
-        
-        
-        # Prepare payload for non-GET requests
-        payload = {}
-        
-        payload["book"] = book
-        
+        _url = API_BASE + "/v1/books"
+
+        # Prepare the request body
+        _payload = {}
+        _payload["book"] = book
+
+        # Encode the remaining fields as query parameters
+        _params = []
+
         # Make the API request
-        result = await make_api_request(url, "POST", payload if payload else None)
+        _result = await make_api_request(_url, "POST", _payload if _payload else None, _params)
         
         
         # Return formatted JSON response
-        import json
-        return json.dumps(result, indent=2)
+        return json.dumps(_result, indent=2)
         
     except Exception as e:
-        # Handle any errors that occur during execution
-        import json
+        # Handle any errors that occur during execution, without builtins
+        # such as type() that an argument of the same name would shadow
         error_result = {
-            "error": f"Tool execution failed: {str(e)}",
+            "error": f"Tool execution failed: {e}",
             "tool_name": "create_book",
-            "error_type": type(e).__name__
+            "error_type": e.__class__.__name__
         }
         return json.dumps(error_result, indent=2)
 
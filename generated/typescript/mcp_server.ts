@@ -0,0 +1,260 @@
+#!/usr/bin/env node
+/**
+ * Bookstore Server - MCP server auto-generated from Protocol Buffers
+ *
+ * Look up books in the bookstore catalog and add new ones.
+ *
+ * Requires the @modelcontextprotocol/sdk, undici and zod packages.
+ */
+
+import { McpServer } from "@modelcontextprotocol/sdk/server/mcp.js";
+import { StdioServerTransport } from "@modelcontextprotocol/sdk/server/stdio.js";
+import { Agent } from "undici";
+import { z } from "zod";
+
+const API_BASE = "http://localhost:8080";
+
+// Accept self-signed certificates of the API, as the Python target does.
+// Only API requests use this agent; the rest of the process still verifies.
+const API_DISPATCHER = new Agent({ connect: { rejectUnauthorized: false } });
+
+// Initialize the MCP server
+const server = new McpServer(
+  { name: "Bookstore Server", version: "1.0.0" },
+  { instructions: "Look up books in the bookstore catalog and add new ones." },
+);
+
+/** Make a HTTP request, wrapping a response_body field back under its key. */
+async function makeApiRequest(
+  url: string,
+  method: string,
+  payload?: unknown,
+  params: [string, string][] = [],
+  responseBody?: string,
+): Promise<unknown> {
+  const query = new URLSearchParams(params).toString();
+  try {
+    const response = await fetch(query ? url + "?" + query : url, {
+      method,
+      headers: { "Content-Type": "application/json" },
+      body: payload === undefined ? undefined : JSON.stringify(payload),
+      signal: AbortSignal.timeout(30000),
+      // Node's fetch is undici, which takes the agent as its dispatcher
+      dispatcher: API_DISPATCHER,
+    } as RequestInit);
+    const text = await response.text();
+    if (!response.ok) {
+      return { error: "HTTP " + response.status + ": " + text };
+    }
+
+    // HEAD and OPTIONS answer with headers rather than a body
+    const verb = method.toUpperCase();
+    if ((verb === "HEAD" || verb === "OPTIONS") && !text) {
+      return { status_code: response.status, headers: Object.fromEntries(response.headers) };
+    }
+
+    // Handle DELETE responses that might be empty
+    if (verb === "DELETE" && (response.status === 200 || response.status === 204)) {
+      return { success: true, message: "Resource deleted successfully" };
+    }
+
+    let result: unknown;
+    try {
+      result = JSON.parse(text);
+    } catch {
+      return { success: true };
+    }
+    return responseBody ? { [responseBody]: result } : result;
+  } catch (e) {
+    return { error: String(e) };
+  }
+}
+
+/** Whether a payload is worth sending, following Python truthiness. */
+function hasContent(value: unknown): boolean {
+  if (Array.isArray(value)) {
+    return value.length > 0;
+  }
+  if (value !== null && typeof value === "object") {
+    return Object.keys(value).length > 0;
+  }
+  return Boolean(value);
+}
+
+/** Describe oneof groups that have more than one member set. */
+function findOneofConflicts(args: Record<string, unknown>, groups: [string[], string, string[]][]): string[] {
+  const conflicts: string[] = [];
+  for (const [path, name, members] of groups) {
+    const value = nestedValue(args, path);
+    if (value === null || typeof value !== "object" || Array.isArray(value)) {
+      continue;
+    }
+    const message = value as Record<string, unknown>;
+    const present = members.filter((member) => message[member] != null);
+    if (present.length > 1) {
+      const prefix = path.map((key) => key + ".").join("");
+      conflicts.push(
+        "Only one of " + members.map((m) => prefix + m).join(", ") + " may be set for oneof '" + name +
+          "', got " + present.map((m) => prefix + m).join(", "),
+      );
+    }
+  }
+  return conflicts;
+}
+
+/**
+ * Expand a path template variable. Single-segment variables percent-escape
+ * every reserved character including "/", multi-segment ones keep "/" as
+ * the separator.
+ */
+function pathValue(name: string, value: unknown, pattern?: string, multiSegment = false): string {
+  if (value == null) {
+    throw new Error("missing value for path parameter " + name);
+  }
+  const text = String(value);
+  if (pattern && !new RegExp(pattern).test(text)) {
+    throw new Error(name + " must match the pattern " + pattern + ", got " + JSON.stringify(text));
+  }
+  const escape = (part: string) =>
+    encodeURIComponent(part).replace(/[!'()*]/g, (c) => "%" + c.charCodeAt(0).toString(16).toUpperCase());
+  return multiSegment ? text.split("/").map(escape).join("/") : escape(text);
+}
+
+/** Read a nested field of a message argument, undefined if any level is unset. */
+function nestedValue(value: unknown, keys: string[]): unknown {
+  for (const key of keys) {
+    if (value === null || typeof value !== "object" || Array.isArray(value)) {
+      return undefined;
+    }
+    value = (value as Record<string, unknown>)[key];
+  }
+  return value;
+}
+
+/**
+ * Encode a value as grpc-gateway query parameters. Nested messages become
+ * dotted paths (a.b=c), repeated fields repeat the key and booleans use
+ * their JSON spelling. Map fields, listed in maps by their path below the
+ * argument ("" for the argument itself), become a[key]=value.
+ */
+function addQueryParam(params: [string, string][], name: string, value: unknown, maps: string[] = [], path = ""): void {
+  if (value == null) {
+    return;
+  }
+  if (Array.isArray(value)) {
+    for (const item of value) {
+      addQueryParam(params, name, item, maps, path);
+    }
+  } else if (typeof value === "object" && maps.includes(path)) {
+    for (const [key, item] of Object.entries(value)) {
+      addQueryParam(params, name + "[" + key + "]", item);
+    }
+  } else if (typeof value === "object") {
+    for (const [key, item] of Object.entries(value)) {
+      addQueryParam(params, name + "." + key, item, maps, path ? path + "." + key : key);
+    }
+  } else {
+    params.push([name, String(value)]);
+  }
+}
+
+// Message types
+
+const BookSchema: z.ZodTypeAny = z.object({
+  "title": z.string(),
+  "author": z.string(),
+  "pages": z.number().int().min(-2147483648).max(2147483647),
+});
+
+// MCP Tools
+
+server.registerTool(
+  "get_book",
+  {
+    description: "Get a book by ID\n\nHTTP: GET /v1/books/{book_id}\n\nReturns:\n- book_id (string, read-only): Assigned by the server when the book is created\n- title (string)\n- author (string)\n- pages (integer)",
+    inputSchema: {
+      "book_id": z.string().describe("The ID of the book to retrieve"),
+    },
+    annotations: { readOnlyHint: true },
+  },
+  async (args) => {
+    try {
+      let result: unknown;
+
+      // Construct the URL
+      const url = API_BASE + "/v1/books/" + pathValue("book_id", args.book_id, undefined, false);
+
+      // Prepare the request body
+      const payload: unknown = undefined;
+
+      // Encode the remaining fields as query parameters
+      const params: [string, string][] = [];
+
+      // Make the API request
+      result = await makeApiRequest(url, "GET", hasContent(payload) ? payload : undefined, params);
+
+      // Return formatted JSON response
+      return textResult(result);
+    } catch (e) {
+      // Handle any errors that occur during execution
+      return textResult({
+        error: "Tool execution failed: " + String(e),
+        tool_name: "get_book",
+        error_type: e instanceof Error ? e.name : typeof e,
+      });
+    }
+  },
+);
+
+server.registerTool(
+  "create_book",
+  {
+    description: "Create a new book in the system.\n\n INSTRUCTIONS:\n   1. For each required field:\n      - If the user has not provided a value , prompt the user to supply it (otherwise the request will fail).\n   2. For optional fields:\n      - If not set by the user, do not set the field in the request and omit them.\n\nHTTP: POST /v1/books\n\nReturns:\n- book_id (string, read-only): Assigned by the server when the book is created\n- title (string)\n- author (string)\n- pages (integer)",
+    inputSchema: {
+      "book": z.lazy(() => BookSchema).describe("The book object to create."),
+    },
+  },
+  async (args) => {
+    try {
+      let result: unknown;
+
+      // Construct the URL
+      const url = API_BASE + "/v1/books";
+
+      // Prepare the request body
+      const payload: Record<string, unknown> = {};
+      payload["book"] = args.book;
+
+      // Encode the remaining fields as query parameters
+      const params: [string, string][] = [];
+
+      // Make the API request
+      result = await makeApiRequest(url, "POST", hasContent(payload) ? payload : undefined, params);
+
+      // Return formatted JSON response
+      return textResult(result);
+    } catch (e) {
+      // Handle any errors that occur during execution
+      return textResult({
+        error: "Tool execution failed: " + String(e),
+        tool_name: "create_book",
+        error_type: e instanceof Error ? e.name : typeof e,
+      });
+    }
+  },
+);
+
+/** Wrap a JSON value as the tool's text content. */
+function textResult(value: unknown) {
+  return { content: [{ type: "text" as const, text: JSON.stringify(value, null, 2) }] };
+}
+
+async function main() {
+  // Run the MCP server over stdio
+  await server.connect(new StdioServerTransport());
+}
+
+main().catch((error) => {
+  console.error(error);
+  process.exit(1);
+});
@@ -3,14 +3,18 @@ module proto-to-mcp-tutorial
 go 1.24.6
 
 require (
+	github.com/google/jsonschema-go v0.3.0
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.27.2
+	github.com/modelcontextprotocol/go-sdk v1.2.0
 	google.golang.org/genproto/googleapis/api v0.0.0-20250826171959-ef028d996bc1
 	google.golang.org/grpc v1.75.0
 	google.golang.org/protobuf v1.36.8
 )
 
 require (
+	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
 	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/oauth2 v0.30.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250818200422-3122310a409c // indirect
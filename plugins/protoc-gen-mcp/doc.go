@@ -0,0 +1,72 @@
+// Command protoc-gen-mcp generates MCP servers from protobuf services whose
+// methods are annotated with the mcp.v1 options.
+//
+// Options are passed as --mcp_opt=key=value,key=value:
+//
+//	target                 python (default), go, typescript or manifest
+//	backend                http (default) or grpc, the default tool backend
+//	name_style             proto (default) or json argument names
+//	additional_bindings    select (default) or split
+//	skip_unspecified_enums leave *_UNSPECIFIED values out of enums
+//	api_base               base URL of the HTTP API (http://localhost:8080)
+//	verify_tls             verify TLS certificates of the HTTP API
+//	grpc_target            address of the gRPC server (localhost:9090)
+//	server_name            MCP server name
+//	out_file               generated file name
+//	template               user template file or directory, see below
+//
+// # Templates
+//
+// The python and typescript targets are rendered with text/template. The
+// template option replaces the built-in template with a file, which renders
+// to the target's output file, or with a directory in which every NAME.tmpl
+// renders to a file called NAME. Files starting with "_" only define
+// templates for the others. Paths are relative to where protoc runs.
+//
+// User templates can include or redefine the built-in templates of the
+// target: "mcp_server" is the whole server and "http_request" renders the
+// statements calling one HTTP binding, as used by the request func.
+//
+// Templates are executed with an *MCPServer. These fields form the stable
+// data model:
+//
+//	MCPServer     Name, Version, Instructions, APIBase, VerifyTLS, NameStyle,
+//	              Files ([]*protogen.File), Services ([]*protogen.Service),
+//	              Methods ([]*MCPMethod), Types ([]*SchemaDef), GRPCTarget,
+//	              UsesGRPC
+//	MCPMethod     ToolName, Title, Description, BaseURL, Backend, GRPCMethod,
+//	              GRPCTarget, Service, Method, Input, Output, Parameters,
+//	              Outputs, InputSchema, OutputSchema, Annotations, Bindings,
+//	              HTTPInfo (the primary binding), Oneofs, and the method
+//	              MapPaths PARAMETER listing map fields for query strings
+//	MCPParameter  Name, ProtoName, Type, Required, ReadOnly, Description,
+//	              Examples, Fields, Schema
+//	HTTPInfo      Method, Path, Template, Body, ResponseBody, PathParams,
+//	              QueryParams, BodyParams, BodyParameter
+//	JSONSchema    the JSON Schema keywords, plus IsRequired, IsNullable,
+//	              RefName and SortedDefs
+//	SchemaDef     Name, Schema
+//	ToolAnnotations  Hints, a list of {Name, Value}
+//
+// Besides the text/template builtins, every template can use:
+//
+//	indent TEXT N       indent the non-blank lines of TEXT by N spaces
+//	contains S SUB      whether S contains SUB
+//	join LIST SEP       strings.Join
+//	quote S             a double-quoted string literal
+//	lower, upper        change the case of a string
+//	snakeCase, camelCase, pascalCase, kebabCase
+//	                    convert identifiers such as "GetBook" or "book_id"
+//	toJSON V            V, typically a schema, as compact JSON
+//	toPrettyJSON V      V as JSON indented by two spaces
+//	schemaType SCHEMA   a short type such as "string" or "array<Book>"
+//	dotted PATH NAME    PATH and NAME joined by dots, as in "book.source"
+//	chunks TEXT N       TEXT split into strings of at most N bytes
+//	bindingChoices BINDINGS
+//	                    the if/elif/else chain picking an HTTP binding
+//	request METHOD BINDING
+//	                    the "http_request" template for one binding
+//
+// together with the target's own helpers, such as pyType and pyPath for
+// python or zodParam and tsPath for typescript.
+package main
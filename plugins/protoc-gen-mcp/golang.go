@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const (
+	contextPackage    = protogen.GoImportPath("context")
+	jsonPackage       = protogen.GoImportPath("encoding/json")
+	jsonschemaPackage = protogen.GoImportPath("github.com/google/jsonschema-go/jsonschema")
+	mcpPackage        = protogen.GoImportPath("github.com/modelcontextprotocol/go-sdk/mcp")
+	protojsonPackage  = protogen.GoImportPath("google.golang.org/protobuf/encoding/protojson")
+	protoPackage      = protogen.GoImportPath("google.golang.org/protobuf/proto")
+)
+
+// generateGoServer writes a <file>_mcp.pb.go next to the gRPC stubs of every
+// file with tools. Each service gets a Register<Service>MCPTools function
+// adding one tool per method, backed by its generated client, and a
+// New<Service>MCPServer constructor that can be served over stdio or
+// streamable HTTP.
+func generateGoServer(gen *protogen.Plugin, server *MCPServer) error {
+	for _, file := range server.Files {
+		var services []*protogen.Service
+		methods := map[*protogen.Service][]*MCPMethod{}
+		seen := map[*protogen.Method]bool{}
+		for _, method := range server.Methods {
+			// Split additional bindings collapse back into the method's tool
+			if method.Service.Desc.ParentFile() != file.Desc || seen[method.Method] {
+				continue
+			}
+			if method.Method.Desc.IsStreamingClient() || method.Method.Desc.IsStreamingServer() {
+				return fmt.Errorf("%s: the go target only supports unary methods", method.Method.Desc.FullName())
+			}
+			seen[method.Method] = true
+
+			if methods[method.Service] == nil {
+				services = append(services, method.Service)
+			}
+			methods[method.Service] = append(methods[method.Service], method)
+		}
+		if len(services) == 0 {
+			continue
+		}
+
+		g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+"_mcp.pb.go", file.GoImportPath)
+		g.P("// Code generated by protoc-gen-mcp. DO NOT EDIT.")
+		g.P("// source: ", file.Desc.Path())
+		g.P()
+		g.P("package ", file.GoPackageName)
+		g.P()
+
+		for _, service := range services {
+			if err := generateGoService(g, server, service, methods[service]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func generateGoService(g *protogen.GeneratedFile, server *MCPServer, service *protogen.Service, methods []*MCPMethod) error {
+	clientName := service.GoName + "Client"
+
+	g.P("// New", service.GoName, "MCPServer returns an MCP server exposing the tools of ", service.GoName, ".")
+	g.P("// Serve it with Run(ctx, &mcp.StdioTransport{}) or through mcp.NewStreamableHTTPHandler.")
+	g.P("func New", service.GoName, "MCPServer(client ", clientName, ") *", mcpPackage.Ident("Server"), " {")
+	g.P("server := ", mcpPackage.Ident("NewServer"), "(&", mcpPackage.Ident("Implementation"), "{Name: ", strconv.Quote(server.Name), ", Version: ", strconv.Quote(server.Version), "}, &", mcpPackage.Ident("ServerOptions"), "{")
+	if server.Instructions != "" {
+		g.P("Instructions: ", strconv.Quote(server.Instructions), ",")
+	}
+	g.P("})")
+	g.P("Register", service.GoName, "MCPTools(server, client)")
+	g.P("return server")
+	g.P("}")
+	g.P()
+
+	g.P("// Register", service.GoName, "MCPTools adds one tool per annotated method of ", service.GoName, " to server.")
+	g.P("func Register", service.GoName, "MCPTools(server *", mcpPackage.Ident("Server"), ", client ", clientName, ") {")
+	g.P("unmarshal := ", protojsonPackage.Ident("UnmarshalOptions"), "{}")
+	g.P("marshal := ", protojsonPackage.Ident("MarshalOptions"), "{UseProtoNames: ", server.NameStyle != "json", ", EmitUnpopulated: true}")
+	g.P()
+	g.P("// resolve returns a tool's input schema, along with a copy resolved for")
+	g.P("// checking arguments. The copy closes the request message and every message")
+	g.P("// under $defs, so fields that are not tool inputs, such as output-only ones,")
+	g.P("// are rejected rather than passed on to the RPC.")
+	g.P("resolve := func(data string) (", jsonPackage.Ident("RawMessage"), ", *", jsonschemaPackage.Ident("Resolved"), ") {")
+	g.P("schema := &", jsonschemaPackage.Ident("Schema"), "{}")
+	g.P("if err := ", jsonPackage.Ident("Unmarshal"), "([]byte(data), schema); err != nil {")
+	g.P("panic(err)")
+	g.P("}")
+	g.P("schema.AdditionalProperties = &", jsonschemaPackage.Ident("Schema"), "{Not: &", jsonschemaPackage.Ident("Schema"), "{}}")
+	g.P("for _, def := range schema.Defs {")
+	g.P("def.AdditionalProperties = &", jsonschemaPackage.Ident("Schema"), "{Not: &", jsonschemaPackage.Ident("Schema"), "{}}")
+	g.P("}")
+	g.P("resolved, err := schema.Resolve(nil)")
+	g.P("if err != nil {")
+	g.P("panic(err)")
+	g.P("}")
+	g.P("return ", jsonPackage.Ident("RawMessage"), "(data), resolved")
+	g.P("}")
+	g.P()
+	g.P("// handle checks the arguments against the input schema, which AddTool")
+	g.P("// leaves to the handler, decodes them into in, makes the call and encodes")
+	g.P("// its response. Bad arguments and RPC errors are reported as tool errors.")
+	g.P("handle := func(req *", mcpPackage.Ident("CallToolRequest"), ", input *", jsonschemaPackage.Ident("Resolved"), ", in ", protoPackage.Ident("Message"), ", call func() (", protoPackage.Ident("Message"), ", error)) (*", mcpPackage.Ident("CallToolResult"), ", error) {")
+	g.P("args := map[string]any{}")
+	g.P("var err error")
+	g.P("if len(req.Params.Arguments) > 0 {")
+	g.P("err = ", jsonPackage.Ident("Unmarshal"), "(req.Params.Arguments, &args)")
+	g.P("}")
+	g.P("if err == nil {")
+	g.P("err = input.Validate(args)")
+	g.P("}")
+	g.P("if err == nil && len(req.Params.Arguments) > 0 {")
+	g.P("err = unmarshal.Unmarshal(req.Params.Arguments, in)")
+	g.P("}")
+	g.P("var out ", protoPackage.Ident("Message"))
+	g.P("if err == nil {")
+	g.P("out, err = call()")
+	g.P("}")
+	g.P("if err != nil {")
+	g.P("return &", mcpPackage.Ident("CallToolResult"), "{IsError: true, Content: []", mcpPackage.Ident("Content"), "{&", mcpPackage.Ident("TextContent"), "{Text: err.Error()}}}, nil")
+	g.P("}")
+	g.P("data, err := marshal.Marshal(out)")
+	g.P("if err != nil {")
+	g.P("return nil, err")
+	g.P("}")
+	g.P("return &", mcpPackage.Ident("CallToolResult"), "{")
+	g.P("Content: []", mcpPackage.Ident("Content"), "{&", mcpPackage.Ident("TextContent"), "{Text: string(data)}},")
+	g.P("StructuredContent: ", jsonPackage.Ident("RawMessage"), "(data),")
+	g.P("}, nil")
+	g.P("}")
+	for _, method := range methods {
+		if goNeedsHintPointer(method.Annotations) {
+			g.P()
+			g.P("hint := func(value bool) *bool { return &value }")
+			break
+		}
+	}
+
+	for _, method := range methods {
+		g.P()
+		if err := generateGoTool(g, method); err != nil {
+			return err
+		}
+	}
+	g.P("}")
+	g.P()
+	return nil
+}
+
+func generateGoTool(g *protogen.GeneratedFile, method *MCPMethod) error {
+	inputSchema, err := goSchemaLiteral(method.InputSchema)
+	if err != nil {
+		return err
+	}
+	outputSchema, err := goSchemaLiteral(method.OutputSchema)
+	if err != nil {
+		return err
+	}
+
+	schemaVar := strings.ToLower(method.Method.GoName[:1]) + method.Method.GoName[1:] + "Schema"
+	inputVar := strings.ToLower(method.Method.GoName[:1]) + method.Method.GoName[1:] + "Input"
+	g.P(schemaVar, ", ", inputVar, " := resolve(", inputSchema, ")")
+	g.P("server.AddTool(&", mcpPackage.Ident("Tool"), "{")
+	g.P("Name: ", strconv.Quote(method.ToolName), ",")
+	if method.Title != "" {
+		g.P("Title: ", strconv.Quote(method.Title), ",")
+	}
+	g.P("Description: ", strconv.Quote(method.Description), ",")
+	g.P("InputSchema: ", schemaVar, ",")
+	g.P("OutputSchema: ", jsonPackage.Ident("RawMessage"), "(", outputSchema, "),")
+	if hints := goToolAnnotations(method.Annotations); hints != "" {
+		g.P("Annotations: &", mcpPackage.Ident("ToolAnnotations"), "{", hints, "},")
+	}
+	g.P("}, func(ctx ", contextPackage.Ident("Context"), ", req *", mcpPackage.Ident("CallToolRequest"), ") (*", mcpPackage.Ident("CallToolResult"), ", error) {")
+	g.P("in := &", method.Input.GoIdent, "{}")
+	g.P("return handle(req, ", inputVar, ", in, func() (", protoPackage.Ident("Message"), ", error) { return client.", method.Method.GoName, "(ctx, in) })")
+	g.P("})")
+	return nil
+}
+
+// goSchemaLiteral renders a schema as a Go string literal, a raw string
+// unless the schema itself contains a backquote.
+func goSchemaLiteral(schema *JSONSchema) (string, error) {
+	data, err := json.MarshalIndent(schema, "", "\t")
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(string(data), "`") {
+		return strconv.Quote(string(data)), nil
+	}
+	return "`" + string(data) + "`", nil
+}
+
+// goToolAnnotations renders the hints as mcp.ToolAnnotations fields. The SDK
+// uses plain bools for hints that default to false and pointers, built with
+// the hint helper, for the rest.
+func goToolAnnotations(annotations *ToolAnnotations) string {
+	var fields []string
+	for _, hint := range annotations.Hints() {
+		name := strings.ToUpper(hint.Name[:1]) + hint.Name[1:]
+		switch hint.Name {
+		case "readOnlyHint", "idempotentHint":
+			if hint.Value {
+				fields = append(fields, name+": true")
+			}
+		default:
+			fields = append(fields, fmt.Sprintf("%s: hint(%t)", name, hint.Value))
+		}
+	}
+	return strings.Join(fields, ", ")
+}
+
+func goNeedsHintPointer(annotations *ToolAnnotations) bool {
+	return annotations.DestructiveHint != nil || annotations.OpenWorldHint != nil
+}
@@ -2,13 +2,21 @@ package main
 
 import (
 	"bytes"
+	"encoding/base64"
+	"errors"
+	"flag"
 	"fmt"
+	"net/url"
+	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/pluginpb"
@@ -18,50 +26,244 @@ import (
 	httpannotations "google.golang.org/genproto/googleapis/api/annotations"
 )
 
+var (
+	flags                = flag.NewFlagSet("protoc-gen-mcp", flag.ContinueOnError)
+	skipUnspecifiedEnums = flags.Bool("skip_unspecified_enums", false, "omit *_UNSPECIFIED enum values from tool schemas")
+	nameStyle            = flags.String("name_style", "proto", `field naming for tool arguments and payloads: "proto" (book_id) or "json" (bookId)`)
+	target               = flags.String("target", "python", `generated output: "python" (FastMCP script), "go" (tools backed by the generated gRPC clients), "typescript" (Node server with zod schemas) or "manifest" (tools.json in the shape of an MCP tools/list result)`)
+	backend              = flags.String("backend", "http", `default backend of generated tools: "http" (google.api.http endpoints) or "grpc" (direct gRPC calls)`)
+	additionalBindings   = flags.String("additional_bindings", "select", `how google.api.http additional_bindings are exposed: "select" (one tool picks a binding per call) or "split" (one tool per binding)`)
+	apiBase              = flags.String("api_base", "http://localhost:8080", "base URL of the HTTP API called by python and typescript tools")
+	grpcTarget           = flags.String("grpc_target", "localhost:9090", "address of the gRPC server called by python tools with the grpc backend")
+	serverName           = flags.String("server_name", "", "MCP server name, overriding the mcp.v1.server name option")
+	outFile              = flags.String("out_file", "", "name of the generated file, defaulting to mcp_server.py, mcp_server.ts or tools.json by target")
+	verifyTLS            = flags.Bool("verify_tls", false, "verify TLS certificates of the HTTP API")
+	templatePath         = flags.String("template", "", "template file, or directory of *.tmpl files, rendered instead of the built-in python or typescript server")
+)
+
+// defaultServerVersion is reported by every target when no mcp.v1.server
+// version is set.
+const defaultServerVersion = "0.0.0"
+
+// setParam applies one --mcp_opt key=value pair. A bare boolean key, such as
+// verify_tls, turns the option on.
+func setParam(name, value string) error {
+	f := flags.Lookup(name)
+	if f == nil {
+		var names []string
+		flags.VisitAll(func(f *flag.Flag) {
+			names = append(names, f.Name)
+		})
+		return fmt.Errorf("unknown mcp_opt %q: valid options are %s", name, strings.Join(names, ", "))
+	}
+	if boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() && value == "" {
+		value = "true"
+	}
+	if err := flags.Set(name, value); err != nil {
+		return fmt.Errorf("invalid mcp_opt %s=%q: %w", name, value, err)
+	}
+	return nil
+}
+
+// isAbsoluteURL reports whether text is a URL with a scheme and host, as the
+// api_base option and the base_url service option must be.
+func isAbsoluteURL(text string) bool {
+	parsed, err := url.Parse(text)
+	return err == nil && parsed.Scheme != "" && parsed.Host != ""
+}
+
 func main() {
-	protogen.Options{}.Run(func(gen *protogen.Plugin) error {
+	protogen.Options{
+		ParamFunc: setParam,
+	}.Run(func(gen *protogen.Plugin) error {
 		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
 
+		if *nameStyle != "proto" && *nameStyle != "json" {
+			return fmt.Errorf(`invalid name_style %q: must be "proto" or "json"`, *nameStyle)
+		}
+		if *target != "python" && *target != "go" && *target != "typescript" && *target != "manifest" {
+			return fmt.Errorf(`invalid target %q: must be "python", "go", "typescript" or "manifest"`, *target)
+		}
+		if *backend != "http" && *backend != "grpc" {
+			return fmt.Errorf(`invalid backend %q: must be "http" or "grpc"`, *backend)
+		}
+		if *additionalBindings != "select" && *additionalBindings != "split" {
+			return fmt.Errorf(`invalid additional_bindings %q: must be "select" or "split"`, *additionalBindings)
+		}
+		if !isAbsoluteURL(*apiBase) {
+			return fmt.Errorf("invalid api_base %q: must be an absolute URL such as http://localhost:8080", *apiBase)
+		}
+		if *grpcTarget == "" {
+			return errors.New("invalid grpc_target: must be an address such as localhost:9090")
+		}
+		if *outFile != "" && *target == "go" {
+			return errors.New("out_file is not supported by the go target, which writes one file per proto file")
+		}
+		if *templatePath != "" && *target != "python" && *target != "typescript" {
+			return fmt.Errorf("template is not supported by the %s target, only by python and typescript", *target)
+		}
+
 		// Extract MCP methods from the proto files
-		mcpMethods := extractMCPMethods(gen)
+		mcpMethods, err := extractMCPMethods(gen)
+		if err != nil {
+			return err
+		}
 
 		if len(mcpMethods) == 0 {
 			return nil // No MCP methods found
 		}
 
-		// Generate main server file
-		generateMCPServer(gen, mcpMethods)
+		server, err := extractMCPServer(gen, mcpMethods)
+		if err != nil {
+			return err
+		}
 
-		return nil
+		// Generate main server file
+		switch *target {
+		case "go":
+			return generateGoServer(gen, server)
+		case "typescript":
+			return generateTypeScriptServer(gen, server)
+		case "manifest":
+			return generateManifest(gen, server)
+		}
+		return generateMCPServer(gen, server)
 	})
 }
 
+// MCPServer describes the generated server as a whole. It is the data
+// passed to server templates, including user templates (see doc.go).
+type MCPServer struct {
+	Name         string // server_name option, mcp.v1.server name or the proto package
+	Version      string // mcp.v1.server version, or defaultServerVersion
+	Instructions string
+	Files        []*protogen.File    // Proto files being generated
+	Services     []*protogen.Service // Services with at least one tool
+	Methods      []*MCPMethod
+	Types        []*SchemaDef // Message definitions used by tool inputs
+	NameStyle    string       // "proto" or "json", see the name_style option
+	APIBase      string       // Default base URL of HTTP tools, see the api_base option
+	VerifyTLS    bool         // Whether HTTP tools verify TLS certificates
+	GRPCTarget   string       // Default address of gRPC tools, see the grpc_target option
+
+	// Base64 serialized FileDescriptorSet covering every gRPC tool, so the
+	// server can convert between JSON and protobuf without generated stubs
+	FileDescriptorSet string
+}
+
+// UsesGRPC reports whether any tool calls its gRPC method directly.
+func (s *MCPServer) UsesGRPC() bool {
+	for _, method := range s.Methods {
+		if method.Backend == "grpc" {
+			return true
+		}
+	}
+	return false
+}
+
+// MCPMethod is one MCP tool backed by an RPC method.
 type MCPMethod struct {
-	Service     *protogen.Service
-	Method      *protogen.Method
-	ToolName    string
-	Description string
-	HTTPInfo    *HTTPInfo
-	Input       *protogen.Message
-	Output      *protogen.Message
-	Parameters  []*MCPParameter
+	Service      *protogen.Service
+	Method       *protogen.Method
+	ToolName     string
+	BaseURL      string // Service-specific API base, empty for the api_base option
+	Title        string
+	Description  string
+	HTTPInfo     *HTTPInfo   // Primary HTTP binding
+	Bindings     []*HTTPInfo // Primary binding followed by additional_bindings
+	Backend      string      // "http" or "grpc"
+	GRPCTarget   string      // Service-specific gRPC address, empty for GRPC_TARGET
+	GRPCMethod   string      // gRPC method path, e.g. "/bookstore.v1.BookstoreService/GetBook"
+	Annotations  *ToolAnnotations
+	Input        *protogen.Message
+	Output       *protogen.Message
+	Parameters   []*MCPParameter
+	Outputs      []*MCPParameter // Fields of the response message
+	InputSchema  *JSONSchema
+	OutputSchema *JSONSchema
+	Oneofs       []*OneofConstraint // Mutually exclusive input fields
+}
+
+// MapPaths lists the map fields of a tool parameter, which grpc-gateway reads
+// from query strings as name[key]=value rather than as dotted paths. See
+// JSONSchema.MapPaths.
+func (m *MCPMethod) MapPaths(param *MCPParameter) []string {
+	return param.Schema.MapPaths(m.InputSchema.Defs)
 }
 
+// MCPParameter is a top-level tool argument, or a response field, backed by
+// a message field.
 type MCPParameter struct {
-	Name        string
-	Type        string
+	Name        string // Argument and payload key, following name_style
+	ProtoName   string // Field name as written in the .proto file
+	Type        string // Short type for docs, such as "string" or "array"
 	Required    bool
 	Description string
+	ReadOnly    bool // Set by the server, only present in responses
+	Examples    []string
+	Fields      []*MCPParameter // Nested fields of message-typed parameters
+	Schema      *JSONSchema
+}
+
+// ToolAnnotations holds the MCP behavior hints for a tool. A nil hint is
+// left for the MCP host to default.
+type ToolAnnotations struct {
+	ReadOnlyHint    *bool `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool `json:"openWorldHint,omitempty"`
 }
 
+type ToolHint struct {
+	Name  string
+	Value bool
+}
+
+// Hints returns the hints that are set, keyed by their MCP field names.
+func (a *ToolAnnotations) Hints() []ToolHint {
+	var hints []ToolHint
+	for _, hint := range []struct {
+		name  string
+		value *bool
+	}{
+		{"readOnlyHint", a.ReadOnlyHint},
+		{"destructiveHint", a.DestructiveHint},
+		{"idempotentHint", a.IdempotentHint},
+		{"openWorldHint", a.OpenWorldHint},
+	} {
+		if hint.value != nil {
+			hints = append(hints, ToolHint{Name: hint.name, Value: *hint.value})
+		}
+	}
+	return hints
+}
+
+// HTTPInfo is one google.api.http binding of a method.
 type HTTPInfo struct {
-	Method string
-	Path   string
-	Body   string
+	Method       string
+	Path         string
+	Template     *PathTemplate
+	Body         string // "*", the name of the request field sent as the body, or empty
+	ResponseBody string // Output key of the response field the gateway returns on its own
+
+	// Tool parameters by where this binding carries them
+	PathParams  []*MCPParameter
+	QueryParams []*MCPParameter
+	BodyParams  []*MCPParameter
+}
+
+// BodyParameter returns the parameter named by the body selector, or nil
+// when the binding sends every field ("*") or no body at all.
+func (h *HTTPInfo) BodyParameter() *MCPParameter {
+	if h.Body == "*" || len(h.BodyParams) == 0 {
+		return nil
+	}
+	return h.BodyParams[0]
 }
 
-func extractMCPMethods(gen *protogen.Plugin) []*MCPMethod {
+func extractMCPMethods(gen *protogen.Plugin) ([]*MCPMethod, error) {
 	var mcpMethods []*MCPMethod
+	schemas := newSchemaGenerator()
 
 	for _, file := range gen.Files {
 		if !file.Generate {
@@ -69,101 +271,586 @@ func extractMCPMethods(gen *protogen.Plugin) []*MCPMethod {
 		}
 
 		for _, service := range file.Services {
+			serviceOptions := getServiceOptions(service)
+			if baseURL := serviceOptions.GetBaseUrl(); baseURL != "" && !isAbsoluteURL(baseURL) {
+				return nil, fmt.Errorf("%s: invalid base_url %q: must be an absolute URL such as http://localhost:8080", service.Desc.FullName(), baseURL)
+			}
 			for _, method := range service.Methods {
-				if hasMCPToolAnnotation(method) {
-					mcpMethod := &MCPMethod{
-						Service:     service,
-						Method:      method,
-						ToolName:    generateToolName(method),
-						Description: extractDescription(method),
-						HTTPInfo:    extractHTTPInfo(method),
-						Input:       method.Input,
-						Output:      method.Output,
-						Parameters:  extractParameters(method.Input),
+				if !isMCPToolEnabled(method, serviceOptions) {
+					continue
+				}
+
+				toolOptions := getToolOptions(method)
+				toolName := serviceOptions.GetToolPrefix() + generateToolName(method, toolOptions)
+				bindings, err := extractHTTPBindings(method)
+				if err != nil {
+					return nil, err
+				}
+
+				if toolBackend(toolOptions) == "grpc" && (method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer()) {
+					return nil, fmt.Errorf("%s: the grpc backend only supports unary methods", method.Desc.FullName())
+				}
+
+				if toolBackend(toolOptions) == "http" && *additionalBindings == "split" && len(bindings) > 1 {
+					// The primary binding keeps the tool name, alternates get a suffix
+					for i, binding := range bindings {
+						name := toolName
+						if i > 0 {
+							name = fmt.Sprintf("%s_alt%d", toolName, i)
+						}
+						mcpMethods = append(mcpMethods, newMCPMethod(schemas, method, serviceOptions, toolOptions, name, []*HTTPInfo{binding}))
 					}
-					mcpMethods = append(mcpMethods, mcpMethod)
+					continue
 				}
+
+				mcpMethods = append(mcpMethods, newMCPMethod(schemas, method, serviceOptions, toolOptions, toolName, bindings))
+			}
+		}
+	}
+
+	if err := checkToolNames(mcpMethods); err != nil {
+		return nil, err
+	}
+	return mcpMethods, nil
+}
+
+// toolNamePattern matches the names the MCP specification allows for tools.
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// checkToolNames rejects names MCP does not allow, tools sharing a name,
+// since only one of them would be reachable, and for the python target tools
+// whose names map to the same function name, such as "get-it" and "get_it",
+// and arguments that cannot be parameters of the tool function.
+func checkToolNames(mcpMethods []*MCPMethod) error {
+	names := map[string]*MCPMethod{}
+	identifiers := map[string]*MCPMethod{}
+	for _, method := range mcpMethods {
+		if !toolNamePattern.MatchString(method.ToolName) {
+			return fmt.Errorf("%s: invalid tool name %q: must be 1 to 128 ASCII letters, digits, '_', '-' or '.'", method.Method.Desc.FullName(), method.ToolName)
+		}
+		if other, ok := names[method.ToolName]; ok {
+			return fmt.Errorf("duplicate tool name %q: used by %s and %s", method.ToolName, other.Method.Desc.FullName(), method.Method.Desc.FullName())
+		}
+		names[method.ToolName] = method
+
+		if *target != "python" {
+			continue
+		}
+		identifier := pythonFunctionName(method.ToolName)
+		if other, ok := identifiers[identifier]; ok {
+			return fmt.Errorf("tools %q (%s) and %q (%s) both become the Python function %s", other.ToolName, other.Method.Desc.FullName(), method.ToolName, method.Method.Desc.FullName(), identifier)
+		}
+		identifiers[identifier] = method
+
+		if err := checkPythonArguments(method); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newMCPMethod builds the tool for a method served over the given bindings.
+func newMCPMethod(schemas *schemaGenerator, method *protogen.Method, serviceOptions *mcpannotations.MCPServiceOptions, toolOptions *mcpannotations.MCPToolOptions, toolName string, bindings []*HTTPInfo) *MCPMethod {
+	var httpInfo *HTTPInfo
+	if len(bindings) > 0 {
+		httpInfo = bindings[0]
+	}
+
+	mcpMethod := &MCPMethod{
+		Service:     method.Parent,
+		Method:      method,
+		ToolName:    toolName,
+		BaseURL:     strings.TrimSuffix(serviceOptions.GetBaseUrl(), "/"),
+		Backend:     toolBackend(toolOptions),
+		GRPCTarget:  serviceOptions.GetGrpcTarget(),
+		GRPCMethod:  fmt.Sprintf("/%s/%s", method.Parent.Desc.FullName(), method.Desc.Name()),
+		Title:       toolOptions.GetTitle(),
+		Description: extractDescription(method, toolOptions),
+		HTTPInfo:    httpInfo,
+		Bindings:    bindings,
+		Annotations: extractToolAnnotations(toolOptions, httpInfo),
+		Input:       method.Input,
+		Output:      method.Output,
+		Parameters:  extractParameters(method.Input),
+		Outputs:     extractOutputs(method.Output),
+	}
+	mcpMethod.InputSchema = schemas.messageSchema(method.Input, false)
+	mcpMethod.OutputSchema = schemas.messageSchema(method.Output, true)
+	mcpMethod.Oneofs = mcpMethod.InputSchema.CollectOneofs()
+	for _, param := range mcpMethod.Parameters {
+		param.Schema = mcpMethod.InputSchema.Properties.Get(param.Name)
+	}
+	for _, binding := range bindings {
+		bindParameters(binding, mcpMethod.Parameters)
+	}
+	if len(bindings) > 1 {
+		relaxBindingParameters(mcpMethod)
+	}
+	if mcpMethod.Backend == "http" {
+		requireBindingParameters(mcpMethod)
+	}
+	return mcpMethod
+}
+
+// requireBindingParameters makes parameters that every binding uses in its
+// path, directly or through a nested field, required whatever their proto
+// presence, since no request can be sent without them.
+func requireBindingParameters(mcpMethod *MCPMethod) {
+	if len(mcpMethod.Bindings) == 0 {
+		return
+	}
+
+	changed := false
+	for _, param := range mcpMethod.Parameters {
+		if param.Required {
+			continue
+		}
+		bound := true
+		for _, binding := range mcpMethod.Bindings {
+			bound = bound && usesPathField(binding, param.ProtoName)
+		}
+		if bound {
+			param.Required = true
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	// Keep the required list in field order
+	var required []string
+	for _, param := range mcpMethod.Parameters {
+		if param.Required {
+			required = append(required, param.Name)
+		}
+	}
+	mcpMethod.InputSchema.Required = required
+}
+
+// usesPathField reports whether a path variable of the binding reads the
+// named request field or one of its nested fields.
+func usesPathField(binding *HTTPInfo, protoName string) bool {
+	for _, variable := range binding.Template.Variables() {
+		if variable.FieldPath[0] == protoName {
+			return true
+		}
+	}
+	return false
+}
+
+// relaxBindingParameters makes path parameters that only some bindings use
+// optional, since leaving them out is how callers pick another binding.
+// Fields the proto explicitly marks as required stay required.
+func relaxBindingParameters(mcpMethod *MCPMethod) {
+	for _, field := range mcpMethod.Input.Fields {
+		param := findParameter(mcpMethod.Parameters, fieldName(field))
+		if param == nil || !param.Required || isFieldMarkedRequired(field) {
+			continue
+		}
+
+		bound := 0
+		for _, binding := range mcpMethod.Bindings {
+			if findParameter(binding.PathParams, param.Name) != nil {
+				bound++
 			}
 		}
+		if bound == 0 || bound == len(mcpMethod.Bindings) {
+			continue
+		}
+
+		param.Required = false
+		required := mcpMethod.InputSchema.Required[:0]
+		for _, name := range mcpMethod.InputSchema.Required {
+			if name != param.Name {
+				required = append(required, name)
+			}
+		}
+		mcpMethod.InputSchema.Required = required
+	}
+}
+
+func findParameter(params []*MCPParameter, name string) *MCPParameter {
+	for _, param := range params {
+		if param.Name == name {
+			return param
+		}
+	}
+	return nil
+}
+
+// toolBackend resolves the backend of a tool: the tool annotation wins over
+// the plugin's backend option.
+func toolBackend(toolOptions *mcpannotations.MCPToolOptions) string {
+	switch toolOptions.GetBackend() {
+	case mcpannotations.MCPBackend_MCP_BACKEND_HTTP:
+		return "http"
+	case mcpannotations.MCPBackend_MCP_BACKEND_GRPC:
+		return "grpc"
+	default:
+		return *backend
+	}
+}
+
+func extractMCPServer(gen *protogen.Plugin, mcpMethods []*MCPMethod) (*MCPServer, error) {
+	server := &MCPServer{
+		Name:       *serverName,
+		Methods:    mcpMethods,
+		NameStyle:  *nameStyle,
+		APIBase:    strings.TrimSuffix(*apiBase, "/"),
+		VerifyTLS:  *verifyTLS,
+		GRPCTarget: *grpcTarget,
+	}
+
+	seenTypes := map[string]bool{}
+	for _, method := range mcpMethods {
+		if n := len(server.Services); n == 0 || server.Services[n-1] != method.Service {
+			server.Services = append(server.Services, method.Service)
+		}
+		for _, def := range method.InputSchema.SortedDefs() {
+			if !seenTypes[def.Name] {
+				seenTypes[def.Name] = true
+				server.Types = append(server.Types, def)
+			}
+		}
+	}
+
+	// The first generated file that carries a value for an option provides it
+	for _, file := range gen.Files {
+		if !file.Generate {
+			continue
+		}
+		server.Files = append(server.Files, file)
+
+		serverOptions := getServerOptions(file)
+		if server.Name == "" {
+			server.Name = serverOptions.GetName()
+		}
+		if server.Version == "" {
+			server.Version = serverOptions.GetVersion()
+		}
+		if server.Instructions == "" {
+			server.Instructions = strings.TrimSpace(serverOptions.GetInstructions())
+		}
+	}
+
+	if server.Name == "" {
+		server.Name = string(mcpMethods[0].Service.Desc.ParentFile().Package())
+	}
+	if server.Version == "" {
+		server.Version = defaultServerVersion
+	}
+
+	if server.UsesGRPC() {
+		descriptors, err := encodeFileDescriptorSet(mcpMethods)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode descriptors: %w", err)
+		}
+		server.FileDescriptorSet = descriptors
 	}
 
-	return mcpMethods
+	return server, nil
 }
 
-func hasMCPToolAnnotation(method *protogen.Method) bool {
+// encodeFileDescriptorSet serializes the files defining the gRPC tools'
+// services, with all their imports listed before them.
+func encodeFileDescriptorSet(mcpMethods []*MCPMethod) (string, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := map[string]bool{}
+
+	var add func(file protoreflect.FileDescriptor)
+	add = func(file protoreflect.FileDescriptor) {
+		if seen[file.Path()] {
+			return
+		}
+		seen[file.Path()] = true
+
+		imports := file.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+
+		fileProto := protodesc.ToFileDescriptorProto(file)
+		fileProto.SourceCodeInfo = nil // Comments are already in the tool schemas
+		set.File = append(set.File, fileProto)
+	}
+	for _, method := range mcpMethods {
+		if method.Backend == "grpc" {
+			add(method.Method.Desc.ParentFile())
+		}
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(set)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+func getServerOptions(file *protogen.File) *mcpannotations.MCPServerOptions {
+	options := file.Desc.Options().(*descriptorpb.FileOptions)
+	if options == nil {
+		return nil
+	}
+
+	if !proto.HasExtension(options, mcpannotations.E_Server) {
+		return nil
+	}
+
+	return proto.GetExtension(options, mcpannotations.E_Server).(*mcpannotations.MCPServerOptions)
+}
+
+func isMCPToolEnabled(method *protogen.Method, serviceOptions *mcpannotations.MCPServiceOptions) bool {
+	// An explicit enabled flag on the method wins over the service's expose_all
+	if toolOptions := getToolOptions(method); toolOptions != nil && toolOptions.Enabled != nil {
+		return *toolOptions.Enabled
+	}
+
+	return serviceOptions.GetExposeAll()
+}
+
+func getServiceOptions(service *protogen.Service) *mcpannotations.MCPServiceOptions {
+	options := service.Desc.Options().(*descriptorpb.ServiceOptions)
+	if options == nil {
+		return nil
+	}
+
+	if !proto.HasExtension(options, mcpannotations.E_Service) {
+		return nil
+	}
+
+	return proto.GetExtension(options, mcpannotations.E_Service).(*mcpannotations.MCPServiceOptions)
+}
+
+func getToolOptions(method *protogen.Method) *mcpannotations.MCPToolOptions {
 	options := method.Desc.Options().(*descriptorpb.MethodOptions)
 	if options == nil {
-		return false
+		return nil
 	}
 
 	// Check if method has mcp.v1.tool annotation
 	if !proto.HasExtension(options, mcpannotations.E_Tool) {
-		return false
+		return nil
 	}
 
-	// Get annotation value and check if enabled
-	toolOptions := proto.GetExtension(options, mcpannotations.E_Tool).(*mcpannotations.MCPToolOptions)
-	return toolOptions != nil && toolOptions.Enabled
+	return proto.GetExtension(options, mcpannotations.E_Tool).(*mcpannotations.MCPToolOptions)
 }
 
 func extractParameters(inputType *protogen.Message) []*MCPParameter {
+	return extractMessageParameters(inputType, false, map[protoreflect.FullName]bool{})
+}
+
+func extractOutputs(outputType *protogen.Message) []*MCPParameter {
+	return extractMessageParameters(outputType, true, map[protoreflect.FullName]bool{})
+}
+
+func extractMessageParameters(message *protogen.Message, output bool, visiting map[protoreflect.FullName]bool) []*MCPParameter {
 	var parameters []*MCPParameter
 
-	if inputType != nil {
-		for _, field := range inputType.Fields {
-			param := &MCPParameter{
-				Name:        string(field.Desc.Name()),
-				Type:        getFieldType(field),
-				Required:    isFieldRequired(field),
-				Description: extractFieldDescription(field),
-			}
-			parameters = append(parameters, param)
+	if message == nil {
+		return parameters
+	}
+
+	visiting[message.Desc.FullName()] = true
+	defer delete(visiting, message.Desc.FullName())
+
+	for _, field := range message.Fields {
+		if !isFieldExposed(field, output) {
+			continue
+		}
+
+		param := &MCPParameter{
+			Name:        fieldName(field),
+			ProtoName:   string(field.Desc.Name()),
+			Type:        getFieldType(field),
+			Required:    isFieldRequired(field),
+			Description: extractFieldDescription(field),
+			ReadOnly:    output && hasFieldBehavior(field, httpannotations.FieldBehavior_OUTPUT_ONLY),
+			Examples:    getFieldOptions(field).GetExamples(),
 		}
+
+		// Describe nested message fields, stopping at recursive references
+		if field.Message != nil && !field.Desc.IsMap() && !isWellKnownType(field.Message) && !visiting[field.Message.Desc.FullName()] {
+			param.Fields = extractMessageParameters(field.Message, output, visiting)
+		}
+		parameters = append(parameters, param)
 	}
 
 	return parameters
 }
 
+// isFieldExposed reports whether a field appears in tool inputs or, for
+// output, in responses. Hidden and output-only fields are never tool inputs,
+// input-only fields never appear in responses.
+func isFieldExposed(field *protogen.Field, output bool) bool {
+	if output {
+		return !hasFieldBehavior(field, httpannotations.FieldBehavior_INPUT_ONLY)
+	}
+	return !getFieldOptions(field).GetHidden() && !hasFieldBehavior(field, httpannotations.FieldBehavior_OUTPUT_ONLY)
+}
+
+// fieldName returns the name a field goes by in tool arguments, payloads and
+// schemas. The "json" style matches protojson's default lowerCamelCase output.
+func fieldName(field *protogen.Field) string {
+	if *nameStyle == "json" {
+		return field.Desc.JSONName()
+	}
+	return string(field.Desc.Name())
+}
+
+func getFieldOptions(field *protogen.Field) *mcpannotations.MCPFieldOptions {
+	options, ok := field.Desc.Options().(*descriptorpb.FieldOptions)
+	if !ok || options == nil {
+		return nil
+	}
+
+	if !proto.HasExtension(options, mcpannotations.E_Field) {
+		return nil
+	}
+
+	return proto.GetExtension(options, mcpannotations.E_Field).(*mcpannotations.MCPFieldOptions)
+}
+
 func isFieldRequired(field *protogen.Field) bool {
+	// An explicit required flag on the mcp.v1.field annotation wins
+	if fieldOptions := getFieldOptions(field); fieldOptions != nil && fieldOptions.Required != nil {
+		return *fieldOptions.Required
+	}
+
+	// Members of a oneof exclude each other, so none of them can be required
+	if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
+		return false
+	}
+
+	// AIP-style google.api.field_behavior annotations come next
+	switch {
+	case hasFieldBehavior(field, httpannotations.FieldBehavior_REQUIRED):
+		return true
+	case hasFieldBehavior(field, httpannotations.FieldBehavior_OPTIONAL),
+		hasFieldBehavior(field, httpannotations.FieldBehavior_OUTPUT_ONLY):
+		return false
+	}
+
 	// In proto3, technically all fields are optional, but for business logic:
 	// If the field not marked as optional keyword, consider it required
 	if field.Desc.HasOptionalKeyword() {
 		return false
 	}
 
+	// An absent list or map is the same as an empty one
+	if field.Desc.Cardinality() == protoreflect.Repeated {
+		return false
+	}
+
+	// Singular message fields track presence like optional ones, and a
+	// required recursive reference could never be satisfied
+	if field.Message != nil {
+		return false
+	}
+
 	return true
 }
 
+// isFieldMarkedRequired reports whether the proto explicitly requires the
+// field, through the mcp.v1.field annotation or field_behavior.
+func isFieldMarkedRequired(field *protogen.Field) bool {
+	if fieldOptions := getFieldOptions(field); fieldOptions != nil && fieldOptions.Required != nil {
+		return *fieldOptions.Required
+	}
+	return hasFieldBehavior(field, httpannotations.FieldBehavior_REQUIRED)
+}
+
+func hasFieldBehavior(field *protogen.Field, behavior httpannotations.FieldBehavior) bool {
+	options, ok := field.Desc.Options().(*descriptorpb.FieldOptions)
+	if !ok || options == nil {
+		return false
+	}
+
+	if !proto.HasExtension(options, httpannotations.E_FieldBehavior) {
+		return false
+	}
+
+	for _, b := range proto.GetExtension(options, httpannotations.E_FieldBehavior).([]httpannotations.FieldBehavior) {
+		if b == behavior {
+			return true
+		}
+	}
+	return false
+}
+
 func getFieldType(field *protogen.Field) string {
+	// Maps are JSON objects keyed by the string form of the map key
+	if field.Desc.IsMap() {
+		return "object"
+	}
+
 	// Check if the field is repeated (array/list)
 	if field.Desc.Cardinality() == protoreflect.Repeated {
-		return "list"
+		return "array"
 	}
 
 	switch field.Desc.Kind() {
-	case protoreflect.StringKind:
-		return "string"
-	case protoreflect.Int32Kind, protoreflect.Int64Kind:
-		return "integer"
-	case protoreflect.BoolKind:
-		return "boolean"
-	case protoreflect.MessageKind:
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if isWellKnownType(field.Message) {
+			return wellKnownTypeName(field.Message)
+		}
 		return "object"
 	case protoreflect.EnumKind:
+		if schema := wellKnownEnumSchema(field.Enum); schema != nil {
+			return schema.Type
+		}
 		return "string"
 	default:
-		return "string"
+		return scalarSchema(field.Desc.Kind()).Type
 	}
 }
 
 func extractFieldDescription(field *protogen.Field) string {
-	if field.Comments.Leading != "" {
-		return strings.TrimSpace(string(field.Comments.Leading))
+	description := strings.TrimSpace(getFieldOptions(field).GetDescription())
+	if description == "" {
+		description = strings.TrimSpace(string(field.Comments.Leading))
 	}
-	return ""
+
+	// Agents cannot tell from the schema alone that an update will not change the value
+	if hasFieldBehavior(field, httpannotations.FieldBehavior_IMMUTABLE) {
+		description = joinDescriptions(description, "Immutable: can only be set when the resource is created.")
+	}
+	return description
 }
 
-func generateToolName(method *protogen.Method) string {
+// formatFieldList renders nested parameters as an indented bullet list for docstrings
+func formatFieldList(params []*MCPParameter) string {
+	var lines []string
+	for _, param := range params {
+		line := fmt.Sprintf("- %s (%s", param.Name, param.Type)
+		if param.ReadOnly {
+			line += ", read-only"
+		} else if !param.Required {
+			line += ", optional"
+		}
+		line += ")"
+		if param.Description != "" {
+			line += ": " + strings.Join(strings.Fields(param.Description), " ")
+		}
+		if len(param.Examples) > 0 {
+			line += fmt.Sprintf(" (e.g. %s)", strings.Join(param.Examples, ", "))
+		}
+		lines = append(lines, line)
+		if len(param.Fields) > 0 {
+			for _, nested := range strings.Split(formatFieldList(param.Fields), "\n") {
+				lines = append(lines, "  "+nested)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func generateToolName(method *protogen.Method, toolOptions *mcpannotations.MCPToolOptions) string {
+	// An explicit name on the annotation wins over the RPC name
+	if toolOptions.GetName() != "" {
+		return toolOptions.GetName()
+	}
+
 	methodName := string(method.Desc.Name())
 
 	// Convert to snake_case
@@ -178,29 +865,186 @@ func camelToSnake(str string) string {
 	return strings.ToLower(snake)
 }
 
-func extractDescription(method *protogen.Method) string {
+func extractDescription(method *protogen.Method, toolOptions *mcpannotations.MCPToolOptions) string {
+	// Prefer the agent-specific description so proto comments can stay API-facing
+	if toolOptions.GetDescription() != "" {
+		return strings.TrimSpace(toolOptions.GetDescription())
+	}
 	if method.Comments.Leading != "" {
 		return strings.TrimSpace(string(method.Comments.Leading))
 	}
 	return fmt.Sprintf("Execute %s RPC method", method.Desc.Name())
 }
 
-func extractHTTPInfo(method *protogen.Method) *HTTPInfo {
+// parameterLocation follows grpc-gateway's mapping: fields bound in the path
+// template are path parameters, body "*" sends every other field in the body,
+// a named body sends only that field and everything else is a query parameter.
+// Messages with a nested field in the path still send their other fields.
+func parameterLocation(param *MCPParameter, httpInfo *HTTPInfo) string {
+	for _, variable := range httpInfo.Template.Variables() {
+		if len(variable.FieldPath) == 1 && variable.FieldPath[0] == param.ProtoName {
+			return "path"
+		}
+	}
+
+	if httpInfo.Body == "*" || httpInfo.Body == param.ProtoName {
+		return "body"
+	}
+	return "query"
+}
+
+// bindParameters sorts the tool parameters into the binding's path, query
+// and body parameters.
+func bindParameters(httpInfo *HTTPInfo, params []*MCPParameter) {
+	for _, param := range params {
+		switch parameterLocation(param, httpInfo) {
+		case "path":
+			httpInfo.PathParams = append(httpInfo.PathParams, param)
+		case "body":
+			httpInfo.BodyParams = append(httpInfo.BodyParams, param)
+		default:
+			httpInfo.QueryParams = append(httpInfo.QueryParams, param)
+		}
+	}
+}
+
+// BindingChoice is one branch of the if/else chain that picks an HTTP binding
+// at call time. Keyword is "if", "elif" or "else", or empty for a tool with a
+// single binding; an else without a binding reports that none matched.
+type BindingChoice struct {
+	Keyword string
+	Binding *HTTPInfo
+}
+
+// bindingChoices orders bindings from the most path variables to the fewest,
+// each guarded by a check that its path variables are set. The first binding
+// without path variables always matches and ends the chain.
+func bindingChoices(bindings []*HTTPInfo) []*BindingChoice {
+	if len(bindings) == 1 {
+		return []*BindingChoice{{Binding: bindings[0]}}
+	}
+
+	ordered := append([]*HTTPInfo{}, bindings...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return len(ordered[i].Template.Variables()) > len(ordered[j].Template.Variables())
+	})
+
+	var choices []*BindingChoice
+	for i, binding := range ordered {
+		if len(binding.Template.Variables()) == 0 {
+			if i == 0 {
+				return []*BindingChoice{{Binding: binding}}
+			}
+			return append(choices, &BindingChoice{Keyword: "else", Binding: binding})
+		}
+
+		keyword := "elif"
+		if i == 0 {
+			keyword = "if"
+		}
+		choices = append(choices, &BindingChoice{Keyword: keyword, Binding: binding})
+	}
+	return append(choices, &BindingChoice{Keyword: "else"})
+}
+
+func extractToolAnnotations(toolOptions *mcpannotations.MCPToolOptions, httpInfo *HTTPInfo) *ToolAnnotations {
+	annotations := &ToolAnnotations{}
+
+	// Derive defaults from the HTTP verb, which custom patterns may spell in
+	// lower case
+	if httpInfo != nil {
+		switch strings.ToUpper(httpInfo.Method) {
+		case "GET", "HEAD", "OPTIONS":
+			annotations.ReadOnlyHint = proto.Bool(true)
+		case "DELETE":
+			annotations.DestructiveHint = proto.Bool(true)
+		case "PUT":
+			annotations.IdempotentHint = proto.Bool(true)
+		}
+	}
+
+	if toolOptions == nil {
+		return annotations
+	}
+
+	// Explicit hints on the annotation override the defaults
+	if toolOptions.ReadOnlyHint != nil {
+		annotations.ReadOnlyHint = toolOptions.ReadOnlyHint
+	}
+	if toolOptions.DestructiveHint != nil {
+		annotations.DestructiveHint = toolOptions.DestructiveHint
+	}
+	if toolOptions.IdempotentHint != nil {
+		annotations.IdempotentHint = toolOptions.IdempotentHint
+	}
+	if toolOptions.OpenWorldHint != nil {
+		annotations.OpenWorldHint = toolOptions.OpenWorldHint
+	}
+
+	return annotations
+}
+
+// extractHTTPBindings returns the method's google.api.http rule followed by
+// its additional_bindings, or nil when the method has no HTTP mapping.
+// Bindings with unbound wildcards are skipped, since no argument fills them.
+func extractHTTPBindings(method *protogen.Method) ([]*HTTPInfo, error) {
 	options := method.Desc.Options().(*descriptorpb.MethodOptions)
 	if options == nil {
-		return nil
+		return nil, nil
 	}
 
 	if !proto.HasExtension(options, httpannotations.E_Http) {
-		return nil
+		return nil, nil
 	}
 
 	httpRule := proto.GetExtension(options, httpannotations.E_Http).(*httpannotations.HttpRule)
 	if httpRule == nil {
-		return nil
+		return nil, nil
+	}
+
+	var bindings []*HTTPInfo
+	for _, rule := range append([]*httpannotations.HttpRule{httpRule}, httpRule.AdditionalBindings...) {
+		info, err := extractHTTPInfo(method, rule)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", method.Desc.FullName(), err)
+		}
+		if info.Template.HasUnboundWildcard() {
+			fmt.Fprintf(os.Stderr, "protoc-gen-mcp: %s: skipping http binding %s %s, which has wildcards no field is bound to\n", method.Desc.FullName(), info.Method, info.Path)
+			continue
+		}
+		bindings = append(bindings, info)
+	}
+
+	// An HTTP tool left without bindings could only ever report an error
+	if len(bindings) == 0 && toolBackend(getToolOptions(method)) == "http" {
+		return nil, fmt.Errorf("%s: no http binding is left once bindings with unbound wildcards are skipped", method.Desc.FullName())
+	}
+	return bindings, nil
+}
+
+func extractHTTPInfo(method *protogen.Method, httpRule *httpannotations.HttpRule) (*HTTPInfo, error) {
+	info := &HTTPInfo{Body: httpRule.Body}
+
+	// A selector naming no tool argument would silently send no body
+	if info.Body != "" && info.Body != "*" {
+		field := findField(method.Input, info.Body)
+		if field == nil {
+			return nil, fmt.Errorf("body %q names no field of %s", info.Body, method.Input.Desc.FullName())
+		}
+		if !isFieldExposed(field, false) {
+			return nil, fmt.Errorf("body %q names a hidden or output-only field", info.Body)
+		}
 	}
 
-	info := &HTTPInfo{}
+	if httpRule.ResponseBody != "" {
+		field := findField(method.Output, httpRule.ResponseBody)
+		if field == nil {
+			return nil, fmt.Errorf("response_body %q names no field of %s", httpRule.ResponseBody, method.Output.Desc.FullName())
+		}
+		// The gateway replies with that field's value alone, so the server
+		// wraps it back under its output key to match the output schema
+		info.ResponseBody = fieldName(field)
+	}
 
 	switch pattern := httpRule.Pattern.(type) {
 	case *httpannotations.HttpRule_Get:
@@ -209,31 +1053,78 @@ func extractHTTPInfo(method *protogen.Method) *HTTPInfo {
 	case *httpannotations.HttpRule_Post:
 		info.Method = "POST"
 		info.Path = pattern.Post
-		info.Body = httpRule.Body
 	case *httpannotations.HttpRule_Put:
 		info.Method = "PUT"
 		info.Path = pattern.Put
-		info.Body = httpRule.Body
 	case *httpannotations.HttpRule_Delete:
 		info.Method = "DELETE"
 		info.Path = pattern.Delete
 	case *httpannotations.HttpRule_Patch:
 		info.Method = "PATCH"
 		info.Path = pattern.Patch
-		info.Body = httpRule.Body
+	case *httpannotations.HttpRule_Custom:
+		// Custom kinds such as HEAD or OPTIONS are sent verbatim
+		info.Method = pattern.Custom.GetKind()
+		info.Path = pattern.Custom.GetPath()
+		if info.Method == "" {
+			return nil, fmt.Errorf("custom http pattern %q has no kind", info.Path)
+		}
+	default:
+		return nil, errors.New("http rule has no pattern")
+	}
+
+	template, err := parsePathTemplate(info.Path)
+	if err == nil {
+		err = resolvePathTemplate(template, method.Input)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid http path template %q: %w", info.Path, err)
 	}
+	info.Template = template
 
-	return info
+	return info, nil
 }
 
-func generateMCPServer(gen *protogen.Plugin, mcpMethods []*MCPMethod) {
-	outputFile := gen.NewGeneratedFile("mcp_server.py", ".")
+// findField returns the message field with the given proto name, or nil.
+func findField(message *protogen.Message, name string) *protogen.Field {
+	for _, field := range message.Fields {
+		if string(field.Desc.Name()) == name {
+			return field
+		}
+	}
+	return nil
+}
 
-	funcMap := template.FuncMap{
+// templateFuncs returns the helpers shared by every templated target.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
 		"contains": func(s, substr string) bool {
 			return strings.Contains(s, substr)
 		},
-		"printf": fmt.Sprintf,
+		"printf":       fmt.Sprintf,
+		"quote":        strconv.Quote,
+		"join":         strings.Join,
+		"lower":        strings.ToLower,
+		"upper":        strings.ToUpper,
+		"snakeCase":    snakeCase,
+		"camelCase":    camelCase,
+		"pascalCase":   pascalCase,
+		"kebabCase":    kebabCase,
+		"toJSON":       toJSON,
+		"toPrettyJSON": toPrettyJSON,
+		"schemaType":   schemaTypeName,
+		"dotted": func(path []string, name string) string {
+			return strings.Join(append(append([]string{}, path...), name), ".")
+		},
+		"bindingChoices": bindingChoices,
+		"chunks": func(text string, size int) []string {
+			var chunks []string
+			for len(text) > size {
+				chunks = append(chunks, text[:size])
+				text = text[size:]
+			}
+			return append(chunks, text)
+		},
 		"indent": func(text string, spaces int) string {
 			if text == "" {
 				return text
@@ -251,63 +1142,137 @@ func generateMCPServer(gen *protogen.Plugin, mcpMethods []*MCPMethod) {
 			return strings.Join(result, "\n")
 		},
 	}
+}
 
-	tmpl := template.Must(template.New("mcp_server").Funcs(funcMap).Parse(mcpServerTemplate))
+// outputFileName returns the out_file option, or the target's default name.
+func outputFileName(defaultName string) string {
+	if *outFile != "" {
+		return *outFile
+	}
+	return defaultName
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, mcpMethods); err != nil {
-		return
+func generateMCPServer(gen *protogen.Plugin, server *MCPServer) error {
+	var tmpl *template.Template
+	funcMap := templateFuncs()
+	for name, fn := range map[string]any{
+		"fields":     formatFieldList,
+		"pyType":     pythonAnnotation,
+		"pyParam":    pythonParam,
+		"pyValue":    pythonLiteral,
+		"pyTypeName": pythonTypeName,
+		"oneofs":     pythonOneofGroups,
+		"docstring": func(text string) string {
+			text = strings.ReplaceAll(text, "\\", "\\\\")
+			return strings.ReplaceAll(text, `"""`, `\"\"\"`)
+		},
+		"identifier":  pythonFunctionName,
+		"pyCondition": pythonCondition,
+		"pyPath":      pythonPath,
+		"request": func(method *MCPMethod, binding *HTTPInfo) (string, error) {
+			var buf bytes.Buffer
+			data := struct {
+				Method  *MCPMethod
+				Binding *HTTPInfo
+			}{method, binding}
+			err := tmpl.ExecuteTemplate(&buf, "http_request", data)
+			return buf.String(), err
+		},
+	} {
+		funcMap[name] = fn
 	}
 
-	outputFile.P(buf.String())
+	tmpl = template.Must(template.New("mcp_server").Funcs(funcMap).Parse(mcpServerTemplate))
+	template.Must(tmpl.New("http_request").Parse(httpRequestTemplate))
+
+	return renderTemplates(gen, server, tmpl, "mcp_server.py")
 }
 
+// httpRequestTemplate renders the statements that call one HTTP binding and
+// leave the decoded response in _result. Locals start with an underscore so
+// they cannot clash with tool arguments.
+const httpRequestTemplate = `# Construct the URL
+_url = {{if .Method.BaseURL}}{{quote .Method.BaseURL}}{{else}}API_BASE{{end}} + {{pyPath .Binding.Template}}
+
+# Prepare the request body{{if eq .Binding.Body "*"}}
+_payload = {}{{range .Binding.BodyParams}}
+{{if .Required}}_payload["{{.Name}}"] = {{.Name}}{{else}}if {{.Name}} is not None:
+    _payload["{{.Name}}"] = {{.Name}}{{end}}{{end}}{{else}}
+_payload = {{with .Binding.BodyParameter}}{{.Name}}{{else}}None{{end}}{{end}}
+
+# Encode the remaining fields as query parameters
+_params = []{{range .Binding.QueryParams}}
+add_query_param(_params, "{{.Name}}", {{.Name}}{{with $.Method.MapPaths .}}, maps={{toJSON .}}{{end}}){{end}}
+
+# Make the API request
+_result = await make_api_request(_url, "{{.Binding.Method}}", _payload if _payload else None, _params{{if .Binding.ResponseBody}}, response_body={{quote .Binding.ResponseBody}}{{end}})`
+
 const mcpServerTemplate = `#!/usr/bin/env python3
 """
-MCP Server for UPM API - Auto-generated from Protocol Buffers
-
-This server provides access to project management operations
+{{docstring .Name}} - MCP server auto-generated from Protocol Buffers
+{{if .Instructions}}
+{{docstring .Instructions}}
+{{else}}
+This server provides access to {{range $i, $service := .Services}}{{if $i}}, {{end}}{{$service.Desc.Name}}{{end}} operations
 through the Model Context Protocol.
-"""
+{{end}}"""
 
 import os
 import sys
-from typing import Any
+from typing import Annotated, Any, Literal, Optional, Union
 import json
+import re
+from urllib.parse import quote
+{{- if .UsesGRPC}}
+import base64
+{{- end}}
 
 import httpx
+{{- if .UsesGRPC}}
+import grpc
+from google.protobuf import descriptor_pb2, descriptor_pool, json_format, message_factory
+{{- end}}
 from mcp.server.fastmcp import FastMCP
+from mcp.types import ToolAnnotations
+from pydantic import Field
+from typing_extensions import Required, TypedDict
 
-API_BASE = 'http://localhost:8080'
-VERIFY_SSL = False
+API_BASE = {{quote .APIBase}}
+VERIFY_SSL = {{if .VerifyTLS}}True{{else}}False{{end}}
+{{- if .UsesGRPC}}
+GRPC_TARGET = {{quote .GRPCTarget}}
+{{- end}}
 
 # Initialize FastMCP
-mcp = FastMCP('Bookstore Server')
+mcp = FastMCP({{quote .Name}}{{if .Instructions}}, instructions={{quote .Instructions}}{{end}})
+
+# FastMCP takes no version argument, so set it on the underlying low-level
+# server when this SDK release still exposes it
+if hasattr(mcp, "_mcp_server"):
+    mcp._mcp_server.version = {{quote .Version}}
 
-async def make_api_request(url: str, method: str = "GET", payload: dict = None) -> dict[str, Any] | None:
-    """Make a HTTP request to the specified URL."""
+async def make_api_request(url: str, method: str = "GET", payload: Any = None, params: list = None, response_body: str = None) -> dict[str, Any] | None:
+    """Make a HTTP request to the specified URL.
+
+    When the HTTP rule sets response_body, the API returns that field alone and
+    it is wrapped back under its key.
+    """
 
     headers = {
         "Content-Type": "application/json",
     }
-    # Use SSL verification based on environment variable
+    # Verify TLS certificates as set by the verify_tls plugin option
     async with httpx.AsyncClient(verify=VERIFY_SSL) as client:
         try:
-            if method.upper() == "GET":
-                response = await client.get(url, headers=headers, timeout=30.0)
-            elif method.upper() == "PUT":
-                response = await client.put(url, headers=headers, json=payload, timeout=30.0)
-            elif method.upper() == "POST":
-                response = await client.post(url, headers=headers, json=payload, timeout=30.0)
-            elif method.upper() == "DELETE":
-                response = await client.delete(url, headers=headers, timeout=30.0)
-            elif method.upper() == "PATCH":
-                response = await client.patch(url, headers=headers, json=payload, timeout=30.0)
-            else:
-                return {"error": f"Unsupported HTTP method: {method}"}
+            # Any verb, including custom HttpRule kinds, goes through request()
+            response = await client.request(method, url, headers=headers, params=params, json=payload, timeout=30.0)
             
             response.raise_for_status()
             
+            # HEAD and OPTIONS answer with headers rather than a body
+            if method.upper() in ("HEAD", "OPTIONS") and not response.content:
+                return {"status_code": response.status_code, "headers": dict(response.headers)}
+            
             # Handle DELETE responses that might be empty
             if method.upper() == "DELETE":
                 if response.status_code == 200 or response.status_code == 204:
@@ -315,59 +1280,179 @@ async def make_api_request(url: str, method: str = "GET", payload: dict = None)
                 
             # Try to parse JSON, return empty dict if no content
             try:
-                return response.json()
+                result = response.json()
             except:
                 return {"success": True}
+            if response_body:
+                return {response_body: result}
+            return result
                 
         except httpx.HTTPStatusError as e:
             return {"error": f"HTTP {e.response.status_code}: {e.response.text}"}
         except Exception as e:
             return {"error": str(e)}
 
+{{if .UsesGRPC -}}
+# Serialized FileDescriptorSet of the services called over gRPC
+FILE_DESCRIPTOR_SET = base64.b64decode(
+{{- range chunks .FileDescriptorSet 76}}
+    {{quote .}}
+{{- end}}
+)
+
+def load_descriptor_pool() -> descriptor_pool.DescriptorPool:
+    """Build a descriptor pool holding the embedded proto files."""
+    pool = descriptor_pool.DescriptorPool()
+    for file in descriptor_pb2.FileDescriptorSet.FromString(FILE_DESCRIPTOR_SET).file:
+        pool.AddSerializedFile(file.SerializeToString())
+    return pool
+
+DESCRIPTOR_POOL = load_descriptor_pool()
+
+async def make_grpc_request(target: str, method: str, request_type: str, response_type: str, payload: dict) -> dict[str, Any]:
+    """Call a unary gRPC method directly, converting with the proto3 JSON mapping."""
+    try:
+        request_class = message_factory.GetMessageClass(DESCRIPTOR_POOL.FindMessageTypeByName(request_type))
+        response_class = message_factory.GetMessageClass(DESCRIPTOR_POOL.FindMessageTypeByName(response_type))
+        request = json_format.ParseDict(payload, request_class())
+
+        async with grpc.aio.insecure_channel(target) as channel:
+            call = channel.unary_unary(
+                method,
+                request_serializer=request_class.SerializeToString,
+                response_deserializer=response_class.FromString,
+            )
+            response = await call(request, timeout=30.0)
+
+        return json_format.MessageToDict(response, preserving_proto_field_name={{if eq .NameStyle "json"}}False{{else}}True{{end}})
+    except grpc.aio.AioRpcError as e:
+        return {"error": f"gRPC {e.code().name}: {e.details()}"}
+    except Exception as e:
+        return {"error": str(e)}
+
+{{end -}}
+def find_oneof_conflicts(arguments: dict, groups: list) -> list[str]:
+    """Describe oneof groups that have more than one member set."""
+    conflicts = []
+    for path, name, members in groups:
+        value = arguments
+        for key in path:
+            value = value.get(key) if isinstance(value, dict) else None
+        if not isinstance(value, dict):
+            continue
+        present = [member for member in members if value.get(member) is not None]
+        if len(present) > 1:
+            prefix = ".".join(path + [""])
+            conflicts.append(f"Only one of {', '.join(prefix + m for m in members)} may be set for oneof '{name}', got {', '.join(prefix + m for m in present)}")
+    return conflicts
+
+def path_value(name: str, value: Any, pattern: Optional[str] = None, multi_segment: bool = False) -> str:
+    """Expand a path template variable.
+
+    Single-segment variables percent-escape every reserved character
+    including "/", multi-segment ones keep "/" as the separator.
+    """
+    if value is None:
+        raise ValueError(f"missing value for path parameter {name}")
+    if isinstance(value, bool):
+        value = "true" if value else "false"
+    value = str(value)
+    if pattern and not re.fullmatch(pattern, value):
+        raise ValueError(f"{name} must match the pattern {pattern}, got {value!r}")
+    return quote(value, safe="/" if multi_segment else "")
+
+def nested_value(value: Any, keys: list) -> Any:
+    """Read a nested field of a message argument, None if any level is unset."""
+    for key in keys:
+        if not isinstance(value, dict):
+            return None
+        value = value.get(key)
+    return value
+
+def add_query_param(params: list, name: str, value: Any, maps: list = (), path: str = "") -> None:
+    """Encode a value as grpc-gateway query parameters.
+
+    Nested messages become dotted paths (a.b=c), repeated fields repeat the
+    key and booleans use their JSON spelling. Map fields, listed in maps by
+    their path below the argument ("" for the argument itself), become
+    a[key]=value.
+    """
+    if value is None:
+        return
+    if isinstance(value, dict) and path in maps:
+        for key, item in value.items():
+            add_query_param(params, f"{name}[{key}]", item)
+    elif isinstance(value, dict):
+        for key, item in value.items():
+            add_query_param(params, f"{name}.{key}", item, maps, f"{path}.{key}" if path else key)
+    elif isinstance(value, list):
+        for item in value:
+            add_query_param(params, name, item, maps, path)
+    elif isinstance(value, bool):
+        params.append((name, "true" if value else "false"))
+    else:
+        params.append((name, str(value)))
+
+# Message types
+{{range .Types}}
+{{pyTypeName .Name}} = TypedDict({{quote .Name}}, {
+{{- $def := .Schema}}{{range .Schema.Properties}}
+    {{quote .Name}}: {{if $def.IsRequired .Name}}Required[{{pyType .Schema false}}]{{else}}{{pyType .Schema false}}{{end}},
+{{- end}}
+}, total=False){{if .Schema.Description}}
+{{pyTypeName .Name}}.__doc__ = {{quote .Schema.Description}}{{end}}
+{{end}}
 # MCP Tools
 
-{{range .}}
-@mcp.tool()
-async def {{.ToolName}}({{range $i, $param := .Parameters}}{{if $i}}, {{end}}{{$param.Name}}: {{if eq $param.Type "string"}}str{{else if eq $param.Type "integer"}}int{{else if eq $param.Type "boolean"}}bool{{else if eq $param.Type "list"}}list{{else}}dict{{end}}{{if not $param.Required}} = None{{end}}{{end}}) -> str:
-    """{{.Description}}
-    {{if .HTTPInfo}}
-    HTTP: {{.HTTPInfo.Method}} {{.HTTPInfo.Path}}{{end}}
+{{range .Methods}}
+@mcp.tool(name={{quote .ToolName}}{{if .Title}}, title={{quote .Title}}{{end}}{{with .Annotations.Hints}}, annotations=ToolAnnotations({{range $i, $hint := .}}{{if $i}}, {{end}}{{$hint.Name}}={{if $hint.Value}}True{{else}}False{{end}}{{end}}){{end}})
+async def {{identifier .ToolName}}({{if .Parameters}}*, {{end}}{{range $i, $param := .Parameters}}{{if $i}}, {{end}}{{$param.Name}}: {{pyParam $param}}{{end}}) -> str:
+    """{{docstring .Description}}
+    {{if eq .Backend "grpc"}}
+    gRPC: {{.GRPCMethod}}{{else}}{{range .Bindings}}
+    HTTP: {{.Method}} {{.Path}}{{end}}{{end}}
     
     Parameters:{{range .Parameters}}
-    - {{.Name}} ({{.Type}}{{if not .Required}}, optional{{end}}): {{if contains .Description "\n"}}{{indent .Description 6}}{{else}}{{.Description}}{{end}}{{end}}
-    
+    - {{.Name}} ({{.Type}}{{if not .Required}}, optional{{end}}): {{if contains .Description "\n"}}{{indent (docstring .Description) 6}}{{else}}{{docstring .Description}}{{end}}{{if .Examples}} (e.g. {{docstring (join .Examples ", ")}}){{end}}{{if .Fields}}
+{{indent (docstring (fields .Fields)) 6}}{{end}}{{end}}
+    {{if .Oneofs}}
+    Mutually exclusive parameters (set at most one of each group):{{range .Oneofs}}
+    - {{dotted .Path .Name}}: {{join .Fields ", "}}{{end}}
+    {{end}}
     Returns:
-    - str: JSON formatted response from the API containing the result or error information
+    - str: JSON formatted response from the API containing the result or error information{{if .Outputs}}
+{{indent (docstring (fields .Outputs)) 6}}{{end}}
     """
     try:
-        {{if .HTTPInfo}}
-        # Construct the URL
-        url = f"{API_BASE}{{.HTTPInfo.Path}}"
-        {{$httpInfo := .HTTPInfo}}{{range .Parameters}}{{if and .Required (contains $httpInfo.Path (printf "{%s}" .Name))}}
-        url = url.replace("{" + "{{.Name}}" + "}", str({{.Name}})){{end}}{{end}}
-        
-        # Prepare payload for non-GET requests
-        payload = {}
-        {{range .Parameters}}{{if and (ne $httpInfo.Method "GET") (ne $httpInfo.Method "DELETE") (not (contains $httpInfo.Path (printf "{%s}" .Name)))}}
-        {{if .Required}}payload["{{.Name}}"] = {{.Name}}{{else}}if {{.Name}} is not None:
-            payload["{{.Name}}"] = {{.Name}}{{end}}{{end}}{{end}}
-        
-        # Make the API request
-        result = await make_api_request(url, "{{.HTTPInfo.Method}}", payload if payload else None)
+        {{if .Oneofs}}
+        # Reject arguments that set more than one member of a oneof
+        _conflicts = find_oneof_conflicts({{"{"}}{{range $i, $param := .Parameters}}{{if $i}}, {{end}}{{quote $param.Name}}: {{$param.Name}}{{end}}{{"}"}}, {{oneofs .Oneofs}})
+        if _conflicts:
+            return json.dumps({"error": "; ".join(_conflicts), "tool_name": {{quote .ToolName}}}, indent=2)
+        {{end}}{{if eq .Backend "grpc"}}
+        # Call the gRPC method directly
+        _payload = {}{{range .Parameters}}
+        {{if .Required}}_payload["{{.Name}}"] = {{.Name}}{{else}}if {{.Name}} is not None:
+            _payload["{{.Name}}"] = {{.Name}}{{end}}{{end}}
+        _result = await make_grpc_request({{if .GRPCTarget}}{{quote .GRPCTarget}}{{else}}GRPC_TARGET{{end}}, {{quote .GRPCMethod}}, "{{.Input.Desc.FullName}}", "{{.Output.Desc.FullName}}", _payload)
+        {{else if .Bindings}}{{$method := .}}{{if gt (len .Bindings) 1}}
+        # Use the most specific HTTP binding whose path parameters are all set{{end}}{{range bindingChoices .Bindings}}{{if .Keyword}}
+        {{.Keyword}}{{if ne .Keyword "else"}} {{pyCondition .Binding}}{{end}}:
+{{if .Binding}}{{indent (request $method .Binding) 12}}{{else}}            _result = {"error": "No HTTP binding matches the supplied arguments"}{{end}}{{else}}
+{{indent (request $method .Binding) 8}}{{end}}{{end}}
         {{else}}
-        result = {"error": "No HTTP endpoint defined for this method"}{{end}}
+        _result = {"error": "No HTTP endpoint defined for this method"}{{end}}
         
         # Return formatted JSON response
-        import json
-        return json.dumps(result, indent=2)
+        return json.dumps(_result, indent=2)
         
     except Exception as e:
-        # Handle any errors that occur during execution
-        import json
+        # Handle any errors that occur during execution, without builtins
+        # such as type() that an argument of the same name would shadow
         error_result = {
-            "error": f"Tool execution failed: {str(e)}",
-            "tool_name": "{{.ToolName}}",
-            "error_type": type(e).__name__
+            "error": f"Tool execution failed: {e}",
+            "tool_name": {{quote .ToolName}},
+            "error_type": e.__class__.__name__
         }
         return json.dumps(error_result, indent=2)
 
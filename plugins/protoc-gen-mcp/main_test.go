@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	httpannotations "google.golang.org/genproto/googleapis/api/annotations"
+)
+
+func TestSetParam(t *testing.T) {
+	defaults := map[string]string{}
+	flags.VisitAll(func(f *flag.Flag) {
+		defaults[f.Name] = f.Value.String()
+	})
+	defer func() {
+		for name, value := range defaults {
+			flags.Set(name, value)
+		}
+	}()
+
+	tests := []struct {
+		name, value string
+		want        string // Flag value after the call, or the expected error
+		err         bool
+	}{
+		{name: "target", value: "go", want: "go"},
+		{name: "verify_tls", value: "", want: "true"},
+		{name: "verify_tls", value: "false", want: "false"},
+		{name: "verify_tls", value: "maybe", want: `invalid mcp_opt verify_tls="maybe"`, err: true},
+		{name: "api_base", value: "https://books.example.com", want: "https://books.example.com"},
+		{name: "verbose", value: "true", want: `unknown mcp_opt "verbose": valid options are`, err: true},
+	}
+
+	for _, test := range tests {
+		err := setParam(test.name, test.value)
+		if test.err {
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("setParam(%q, %q) error = %v, want %q", test.name, test.value, err, test.want)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("setParam(%q, %q) failed: %v", test.name, test.value, err)
+			continue
+		}
+		if got := flags.Lookup(test.name).Value.String(); got != test.want {
+			t.Errorf("after setParam(%q, %q), %s = %q, want %q", test.name, test.value, test.name, got, test.want)
+		}
+	}
+}
+
+func TestHTTPSelectorErrors(t *testing.T) {
+	tests := []struct {
+		rule *httpannotations.HttpRule
+		err  string
+	}{
+		{rule: &httpannotations.HttpRule{Body: "missing"}, err: `body "missing" names no field of tool.Request`},
+		{rule: &httpannotations.HttpRule{Body: "secret"}, err: `body "secret" names a hidden or output-only field`},
+		{rule: &httpannotations.HttpRule{ResponseBody: "missing"}, err: `response_body "missing" names no field of tool.Request`},
+	}
+
+	for _, test := range tests {
+		test.rule.Pattern = &httpannotations.HttpRule_Post{Post: "/v1/books"}
+		_, err := extractMCPMethods(newTestPlugin(t, testToolFile(test.rule, testBodyMessages()...)))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("extractMCPMethods() error = %v, want %q", err, test.err)
+		}
+	}
+}
+
+func TestCustomHTTPPattern(t *testing.T) {
+	tests := []struct {
+		kind     string
+		readOnly bool
+		err      string
+	}{
+		{kind: "HEAD", readOnly: true},
+		{kind: "head", readOnly: true},
+		{kind: "Options", readOnly: true},
+		{kind: "PURGE"},
+		{kind: "", err: `custom http pattern "/v1/books" has no kind`},
+	}
+
+	for _, test := range tests {
+		rule := &httpannotations.HttpRule{Pattern: &httpannotations.HttpRule_Custom{Custom: &httpannotations.CustomHttpPattern{Kind: test.kind, Path: "/v1/books"}}}
+		methods, err := extractMCPMethods(newTestPlugin(t, testToolFile(rule, testBodyMessages()...)))
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("kind %q: extractMCPMethods() error = %v, want %q", test.kind, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("kind %q: extractMCPMethods failed: %v", test.kind, err)
+		}
+
+		method := methods[0]
+		if method.HTTPInfo.Method != test.kind {
+			t.Errorf("kind %q: method = %q, want it unchanged", test.kind, method.HTTPInfo.Method)
+		}
+		if got := method.Annotations.ReadOnlyHint != nil && *method.Annotations.ReadOnlyHint; got != test.readOnly {
+			t.Errorf("kind %q: readOnlyHint = %t, want %t", test.kind, got, test.readOnly)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ToolManifest is the tools.json document. It has the shape of an MCP
+// tools/list result, so registries and gateways can load it as is.
+type ToolManifest struct {
+	Tools []*ManifestTool `json:"tools"`
+}
+
+// ManifestTool is an MCP Tool definition. The x-http and x-grpc extension
+// blocks tell a gateway how to call the API behind the tool.
+type ManifestTool struct {
+	Name         string           `json:"name"`
+	Title        string           `json:"title,omitempty"`
+	Description  string           `json:"description"`
+	InputSchema  *JSONSchema      `json:"inputSchema"`
+	OutputSchema *JSONSchema      `json:"outputSchema"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+	HTTP         *ManifestHTTP    `json:"x-http,omitempty"`
+	GRPC         *ManifestGRPC    `json:"x-grpc,omitempty"`
+}
+
+// ManifestHTTP lists the HTTP bindings of a tool, primary binding first.
+// Like the generated servers, a caller should use the binding with the most
+// path parameters whose arguments are all set.
+type ManifestHTTP struct {
+	BaseURL  string             `json:"baseUrl,omitempty"` // Empty when the gateway's own API base applies
+	Bindings []*ManifestBinding `json:"bindings"`
+}
+
+// ManifestBinding is one google.api.http rule, with the tool arguments
+// sorted into where they travel. Argument names follow name_style.
+type ManifestBinding struct {
+	Method       string   `json:"method"`
+	Path         string   `json:"path"`
+	Body         string   `json:"body,omitempty"`         // "*" or the argument sent as the whole body
+	ResponseBody string   `json:"responseBody,omitempty"` // Output key the response body is wrapped under
+	PathParams   []string `json:"pathParams,omitempty"`   // Dotted argument paths, in template order
+	QueryParams  []string `json:"queryParams,omitempty"`
+	BodyParams   []string `json:"bodyParams,omitempty"`
+}
+
+// ManifestGRPC describes a tool that calls its gRPC method directly.
+type ManifestGRPC struct {
+	Method string `json:"method"`
+	Target string `json:"target,omitempty"` // Empty when the gateway's own gRPC target applies
+}
+
+// generateManifest writes tools.json describing every tool.
+func generateManifest(gen *protogen.Plugin, server *MCPServer) error {
+	manifest := &ToolManifest{Tools: []*ManifestTool{}}
+	for _, method := range server.Methods {
+		manifest.Tools = append(manifest.Tools, newManifestTool(method))
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to encode tool manifest: %w", err)
+	}
+
+	outputFile := gen.NewGeneratedFile(outputFileName("tools.json"), ".")
+	_, err := outputFile.Write(buf.Bytes())
+	return err
+}
+
+func newManifestTool(method *MCPMethod) *ManifestTool {
+	tool := &ManifestTool{
+		Name:         method.ToolName,
+		Title:        method.Title,
+		Description:  method.Description,
+		InputSchema:  method.InputSchema,
+		OutputSchema: method.OutputSchema,
+	}
+	if len(method.Annotations.Hints()) > 0 {
+		tool.Annotations = method.Annotations
+	}
+
+	if method.Backend == "grpc" {
+		tool.GRPC = &ManifestGRPC{Method: method.GRPCMethod, Target: method.GRPCTarget}
+		return tool
+	}
+	if len(method.Bindings) > 0 {
+		tool.HTTP = &ManifestHTTP{BaseURL: method.BaseURL}
+		for _, binding := range method.Bindings {
+			tool.HTTP.Bindings = append(tool.HTTP.Bindings, newManifestBinding(binding))
+		}
+	}
+	return tool
+}
+
+func newManifestBinding(binding *HTTPInfo) *ManifestBinding {
+	manifestBinding := &ManifestBinding{
+		Method:       binding.Method,
+		Path:         binding.Path,
+		Body:         binding.Body,
+		ResponseBody: binding.ResponseBody,
+		QueryParams:  parameterNames(binding.QueryParams),
+		BodyParams:   parameterNames(binding.BodyParams),
+	}
+	if param := binding.BodyParameter(); param != nil {
+		manifestBinding.Body = param.Name
+	}
+	for _, variable := range binding.Template.Variables() {
+		manifestBinding.PathParams = append(manifestBinding.PathParams, strings.Join(variable.Names, "."))
+	}
+	return manifestBinding
+}
+
+func parameterNames(params []*MCPParameter) []string {
+	var names []string
+	for _, param := range params {
+		names = append(names, param.Name)
+	}
+	return names
+}
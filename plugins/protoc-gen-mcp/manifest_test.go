@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	mcpannotations "proto-to-mcp-tutorial/generated/go/mcp/protobuf"
+
+	httpannotations "google.golang.org/genproto/googleapis/api/annotations"
+)
+
+func TestGenerateManifest(t *testing.T) {
+	defer func(style string) { *nameStyle = style }(*nameStyle)
+	*nameStyle = "json"
+
+	// find posts book_info over HTTP, fetch calls the same RPC over gRPC
+	bookID := testField("book_id", 1, optional, stringType, "")
+	bookID.JsonName = proto.String("bookId")
+	bookInfo := testField("book_info", 2, optional, messageType, ".tool.BookInfo")
+	bookInfo.JsonName = proto.String("bookInfo")
+	rule := &httpannotations.HttpRule{Pattern: &httpannotations.HttpRule_Post{Post: "/v1/books/{book_id}"}, Body: "book_info"}
+	file := testToolFile(rule,
+		testMessage("Request", bookID, bookInfo, testField("note", 3, optional, stringType, "")),
+		testMessage("BookInfo", testField("title", 1, optional, stringType, "")))
+	grpcService := testService("Fetch", ".tool.Request", nil, &mcpannotations.MCPToolOptions{Backend: mcpannotations.MCPBackend_MCP_BACKEND_GRPC, ReadOnlyHint: proto.Bool(true)})
+	grpcService.Name = proto.String("GRPCService")
+	file.Service = append(file.Service, grpcService)
+
+	gen := newTestPlugin(t, file)
+	methods, err := extractMCPMethods(gen)
+	if err != nil {
+		t.Fatalf("extractMCPMethods failed: %v", err)
+	}
+	server, err := extractMCPServer(gen, methods)
+	if err != nil {
+		t.Fatalf("extractMCPServer failed: %v", err)
+	}
+	if err := generateManifest(gen, server); err != nil {
+		t.Fatalf("generateManifest failed: %v", err)
+	}
+
+	response := gen.Response()
+	if name := response.File[0].GetName(); name != "tools.json" {
+		t.Errorf("output file = %s, want tools.json", name)
+	}
+	var manifest map[string][]map[string]any
+	if err := json.Unmarshal([]byte(response.File[0].GetContent()), &manifest); err != nil {
+		t.Fatalf("tools.json is not a tools envelope: %v", err)
+	}
+	tools := manifest["tools"]
+	if len(manifest) != 1 || len(tools) != 2 {
+		t.Fatalf("tools.json = %v, want a tools list of 2 tools", manifest)
+	}
+
+	find, fetch := tools[0], tools[1]
+	wantHTTP := map[string]any{"bindings": []any{map[string]any{
+		"method":      "POST",
+		"path":        "/v1/books/{book_id}",
+		"body":        "bookInfo",
+		"pathParams":  []any{"bookId"},
+		"queryParams": []any{"note"},
+		"bodyParams":  []any{"bookInfo"},
+	}}}
+	if find["name"] != "find" || !reflect.DeepEqual(find["x-http"], wantHTTP) {
+		t.Errorf("%v x-http = %v, want %v", find["name"], find["x-http"], wantHTTP)
+	}
+	if _, ok := find["annotations"]; ok {
+		t.Errorf("find annotations = %v, want them left out", find["annotations"])
+	}
+
+	wantGRPC := map[string]any{"method": "/tool.GRPCService/Fetch"}
+	if fetch["name"] != "fetch" || !reflect.DeepEqual(fetch["x-grpc"], wantGRPC) || fetch["x-http"] != nil {
+		t.Errorf("%v x-grpc = %v, x-http = %v, want %v and no x-http", fetch["name"], fetch["x-grpc"], fetch["x-http"], wantGRPC)
+	}
+	if want := map[string]any{"readOnlyHint": true}; !reflect.DeepEqual(fetch["annotations"], want) {
+		t.Errorf("fetch annotations = %v, want %v", fetch["annotations"], want)
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// PathTemplate is a parsed google.api.http path template:
+//
+//	Template = "/" Segments [ Verb ] ;
+//	Segments = Segment { "/" Segment } ;
+//	Segment  = "*" | "**" | LITERAL | Variable ;
+//	Variable = "{" FieldPath [ "=" Segments ] "}" ;
+//	FieldPath = IDENT { "." IDENT } ;
+//	Verb     = ":" LITERAL ;
+type PathTemplate struct {
+	Segments []*PathSegment
+	Verb     string
+}
+
+// PathSegment is either a literal or a variable bound to a request field.
+type PathSegment struct {
+	Literal  string
+	Variable *PathVariable
+}
+
+// PathVariable binds a request field to one or more path segments.
+type PathVariable struct {
+	FieldPath []string // Proto field names from the request message down
+	Names     []string // The same path as argument keys, following name_style
+	Segments  []string // Segment pattern, "*" when the template gives none
+}
+
+// String returns the variable's field path as written in the template.
+func (v *PathVariable) String() string {
+	return strings.Join(v.FieldPath, ".")
+}
+
+// MultiSegment reports whether the variable may span several path segments,
+// in which case "/" is left unescaped when the value is expanded.
+func (v *PathVariable) MultiSegment() bool {
+	return len(v.Segments) > 1 || v.Segments[0] == "**"
+}
+
+// Pattern returns an anchored regular expression the variable's value must
+// match, or "" when any value is accepted.
+func (v *PathVariable) Pattern() string {
+	if len(v.Segments) == 1 && (v.Segments[0] == "*" || v.Segments[0] == "**") {
+		return ""
+	}
+
+	parts := make([]string, len(v.Segments))
+	for i, segment := range v.Segments {
+		switch segment {
+		case "*":
+			parts[i] = "[^/]+"
+		case "**":
+			parts[i] = ".*"
+		default:
+			parts[i] = regexp.QuoteMeta(segment)
+		}
+	}
+	return "^" + strings.Join(parts, "/") + "$"
+}
+
+// Variables returns the template's variables in order.
+func (t *PathTemplate) Variables() []*PathVariable {
+	var variables []*PathVariable
+	for _, segment := range t.Segments {
+		if segment.Variable != nil {
+			variables = append(variables, segment.Variable)
+		}
+	}
+	return variables
+}
+
+// HasUnboundWildcard reports whether a "*" or "**" segment appears outside
+// any variable. The server matches any value there, but no tool argument
+// supplies one, so such a binding cannot be called.
+func (t *PathTemplate) HasUnboundWildcard() bool {
+	for _, segment := range t.Segments {
+		if segment.Literal == "*" || segment.Literal == "**" {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePathTemplate parses a google.api.http path template.
+func parsePathTemplate(path string) (*PathTemplate, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, errors.New("must start with /")
+	}
+
+	template := &PathTemplate{}
+	rest := path[1:]
+	for {
+		segment := &PathSegment{}
+		if strings.HasPrefix(rest, "{") {
+			end := strings.Index(rest, "}")
+			if end < 0 {
+				return nil, errors.New("unterminated variable")
+			}
+			variable, err := parsePathVariable(rest[1:end])
+			if err != nil {
+				return nil, err
+			}
+			segment.Variable = variable
+			rest = rest[end+1:]
+		} else {
+			end := strings.IndexAny(rest, "/{}")
+			if end < 0 {
+				end = len(rest)
+			}
+			segment.Literal = rest[:end]
+			rest = rest[end:]
+
+			// A colon in the last segment starts the verb
+			if !strings.Contains(rest, "/") {
+				if colon := strings.Index(segment.Literal, ":"); colon >= 0 {
+					rest = segment.Literal[colon:] + rest
+					segment.Literal = segment.Literal[:colon]
+				}
+			}
+			if segment.Literal == "" {
+				return nil, errors.New("empty path segment")
+			}
+		}
+		template.Segments = append(template.Segments, segment)
+
+		switch {
+		case rest == "":
+			return template, nil
+		case strings.HasPrefix(rest, "/"):
+			rest = rest[1:]
+		case strings.HasPrefix(rest, ":"):
+			template.Verb = rest[1:]
+			if template.Verb == "" || strings.ContainsAny(template.Verb, "/{}") {
+				return nil, fmt.Errorf("invalid verb %q", rest)
+			}
+			return template, nil
+		default:
+			return nil, fmt.Errorf("unexpected %q", rest)
+		}
+	}
+}
+
+// fieldIdentifier matches one element of a variable's field path
+var fieldIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func parsePathVariable(text string) (*PathVariable, error) {
+	fieldPath, pattern, hasPattern := strings.Cut(text, "=")
+
+	variable := &PathVariable{Segments: []string{"*"}}
+	for _, name := range strings.Split(strings.TrimSpace(fieldPath), ".") {
+		if !fieldIdentifier.MatchString(name) {
+			return nil, fmt.Errorf("invalid field path %q", fieldPath)
+		}
+		variable.FieldPath = append(variable.FieldPath, name)
+	}
+
+	if hasPattern {
+		variable.Segments = strings.Split(pattern, "/")
+		for i, segment := range variable.Segments {
+			switch {
+			case segment == "":
+				return nil, fmt.Errorf("empty segment in pattern %q", pattern)
+			case segment == "**" && i != len(variable.Segments)-1:
+				return nil, fmt.Errorf("** must be the last segment of pattern %q", pattern)
+			case strings.ContainsAny(segment, "{}:"):
+				return nil, fmt.Errorf("invalid segment %q in pattern %q", segment, pattern)
+			}
+		}
+	}
+	return variable, nil
+}
+
+// resolvePathTemplate checks that every variable names a singular non-message
+// field reachable through singular message fields of the request, all of them
+// tool inputs, and fills in the argument keys used to read it.
+func resolvePathTemplate(template *PathTemplate, input *protogen.Message) error {
+	for _, segment := range template.Segments {
+		if segment.Variable == nil {
+			continue
+		}
+
+		variable := segment.Variable
+		variable.Names = nil
+		message := input
+		for i, name := range variable.FieldPath {
+			var field *protogen.Field
+			if message != nil {
+				for _, candidate := range message.Fields {
+					if string(candidate.Desc.Name()) == name {
+						field = candidate
+						break
+					}
+				}
+			}
+			if field == nil {
+				return fmt.Errorf("no field %q in %s", variable.String(), input.Desc.FullName())
+			}
+			if field.Desc.Cardinality() == protoreflect.Repeated {
+				return fmt.Errorf("field %q is repeated", variable.String())
+			}
+			if !isFieldExposed(field, false) {
+				return fmt.Errorf("field %q is hidden or output-only, so no tool argument supplies it", strings.Join(variable.FieldPath[:i+1], "."))
+			}
+			last := i == len(variable.FieldPath)-1
+			if !last && field.Message == nil {
+				return fmt.Errorf("field %q is not a message", strings.Join(variable.FieldPath[:i+1], "."))
+			}
+			if last && field.Message != nil {
+				return fmt.Errorf("field %q is a message", variable.String())
+			}
+			variable.Names = append(variable.Names, fieldName(field))
+			message = field.Message
+		}
+	}
+	return nil
+}
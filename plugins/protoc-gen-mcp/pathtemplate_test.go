@@ -0,0 +1,371 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	mcpannotations "proto-to-mcp-tutorial/generated/go/mcp/protobuf"
+
+	httpannotations "google.golang.org/genproto/googleapis/api/annotations"
+)
+
+func TestParsePathTemplate(t *testing.T) {
+	tests := []struct {
+		path     string
+		segments []string // Literals as written, variables as {field.path=pattern}
+		verb     string
+	}{
+		{path: "/v1/books", segments: []string{"v1", "books"}},
+		{path: "/v1/books/{book_id}", segments: []string{"v1", "books", "{book_id=*}"}},
+		{path: "/v1/{book.id}", segments: []string{"v1", "{book.id=*}"}},
+		{path: "/v1/{name=shelves/*/books/*}", segments: []string{"v1", "{name=shelves/*/books/*}"}},
+		{path: "/v1/files/{path=**}", segments: []string{"v1", "files", "{path=**}"}},
+		{path: "/v1/{name=shelves/*}/books/**", segments: []string{"v1", "{name=shelves/*}", "books", "**"}},
+		{path: "/v1/shelves/{name}:move", segments: []string{"v1", "shelves", "{name=*}"}, verb: "move"},
+		{path: "/v1/books:search", segments: []string{"v1", "books"}, verb: "search"},
+		{path: "/v1/*/books", segments: []string{"v1", "*", "books"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			template, err := parsePathTemplate(test.path)
+			if err != nil {
+				t.Fatalf("parsePathTemplate(%q) failed: %v", test.path, err)
+			}
+
+			var segments []string
+			for _, segment := range template.Segments {
+				if segment.Variable == nil {
+					segments = append(segments, segment.Literal)
+					continue
+				}
+				variable := segment.Variable
+				segments = append(segments, "{"+variable.String()+"="+strings.Join(variable.Segments, "/")+"}")
+			}
+			if !reflect.DeepEqual(segments, test.segments) {
+				t.Errorf("segments = %q, want %q", segments, test.segments)
+			}
+			if template.Verb != test.verb {
+				t.Errorf("verb = %q, want %q", template.Verb, test.verb)
+			}
+		})
+	}
+}
+
+func TestParsePathTemplateErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"v1/books",
+		"/",
+		"/v1//books",
+		"/v1/books/",
+		"/v1/{book_id",
+		"/v1/{}",
+		"/v1/{book-id}",
+		"/v1/{book..id}",
+		"/v1/{name=}",
+		"/v1/{name=shelves//books}",
+		"/v1/{name=**/books}",
+		"/v1/{name=shelves/{id}}",
+		"/v1/books:",
+		"/v1/{name}x",
+	}
+
+	for _, path := range tests {
+		if template, err := parsePathTemplate(path); err == nil {
+			t.Errorf("parsePathTemplate(%q) = %+v, want an error", path, template)
+		}
+	}
+}
+
+func TestPathVariablePattern(t *testing.T) {
+	tests := []struct {
+		path         string
+		pattern      string
+		multiSegment bool
+	}{
+		{path: "/{name}", pattern: "", multiSegment: false},
+		{path: "/{name=*}", pattern: "", multiSegment: false},
+		{path: "/{name=**}", pattern: "", multiSegment: true},
+		{path: "/{name=shelves/*}", pattern: `^shelves/[^/]+$`, multiSegment: true},
+		{path: "/{name=shelves/*/books/**}", pattern: `^shelves/[^/]+/books/.*$`, multiSegment: true},
+		{path: "/{name=v1.0/*}", pattern: `^v1\.0/[^/]+$`, multiSegment: true},
+	}
+
+	for _, test := range tests {
+		template, err := parsePathTemplate(test.path)
+		if err != nil {
+			t.Fatalf("parsePathTemplate(%q) failed: %v", test.path, err)
+		}
+		variable := template.Variables()[0]
+		if got := variable.Pattern(); got != test.pattern {
+			t.Errorf("%s: Pattern() = %q, want %q", test.path, got, test.pattern)
+		}
+		if got := variable.MultiSegment(); got != test.multiSegment {
+			t.Errorf("%s: MultiSegment() = %t, want %t", test.path, got, test.multiSegment)
+		}
+	}
+}
+
+func TestHasUnboundWildcard(t *testing.T) {
+	tests := map[string]bool{
+		"/v1/books/{book_id}":     false,
+		"/v1/{name=shelves/*}":    false,
+		"/v1/*/books":             true,
+		"/v1/{name=shelves/*}/**": true,
+	}
+
+	for path, want := range tests {
+		template, err := parsePathTemplate(path)
+		if err != nil {
+			t.Fatalf("parsePathTemplate(%q) failed: %v", path, err)
+		}
+		if got := template.HasUnboundWildcard(); got != want {
+			t.Errorf("%s: HasUnboundWildcard() = %t, want %t", path, got, want)
+		}
+	}
+}
+
+func TestResolvePathTemplate(t *testing.T) {
+	input := testRequestMessage(t)
+
+	tests := []struct {
+		path  string
+		names [][]string // Argument keys of each variable
+		err   string
+	}{
+		{path: "/v1/books/{book_id}", names: [][]string{{"book_id"}}},
+		{path: "/v1/{book.shelf_name=shelves/*}/books", names: [][]string{{"book", "shelf_name"}}},
+		{path: "/v1/books/{book_id}/{book.shelf_name}", names: [][]string{{"book_id"}, {"book", "shelf_name"}}},
+		{path: "/v1/*/books/{book_id}", names: [][]string{{"book_id"}}},
+		{path: "/v1/{missing}", err: `no field "missing"`},
+		{path: "/v1/{book}", err: `field "book" is a message`},
+		{path: "/v1/{book_id.id}", err: `field "book_id" is not a message`},
+		{path: "/v1/{tags}", err: `field "tags" is repeated`},
+		{path: "/v1/{book.missing}", err: `no field "book.missing"`},
+		{path: "/v1/{secret}", err: `field "secret" is hidden or output-only`},
+		{path: "/v1/{book.etag}", err: `field "book.etag" is hidden or output-only`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			template, err := parsePathTemplate(test.path)
+			if err != nil {
+				t.Fatalf("parsePathTemplate(%q) failed: %v", test.path, err)
+			}
+
+			err = resolvePathTemplate(template, input)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("resolvePathTemplate() error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolvePathTemplate() failed: %v", err)
+			}
+
+			var names [][]string
+			for _, variable := range template.Variables() {
+				names = append(names, variable.Names)
+			}
+			if !reflect.DeepEqual(names, test.names) {
+				t.Errorf("names = %q, want %q", names, test.names)
+			}
+		})
+	}
+}
+
+func TestPathParametersRequired(t *testing.T) {
+	get := func(path string, additional ...*httpannotations.HttpRule) *httpannotations.HttpRule {
+		return &httpannotations.HttpRule{Pattern: &httpannotations.HttpRule_Get{Get: path}, AdditionalBindings: additional}
+	}
+
+	tests := []struct {
+		name     string
+		rule     *httpannotations.HttpRule
+		backend  mcpannotations.MCPBackend
+		required []string
+	}{
+		{
+			name:     "nested field",
+			rule:     &httpannotations.HttpRule{Pattern: &httpannotations.HttpRule_Patch{Patch: "/v1/{book.shelf_name=shelves/*}"}, Body: "book"},
+			required: []string{"book_id", "book"},
+		},
+		{name: "optional keyword", rule: get("/v1/isbn/{isbn}"), required: []string{"book_id", "isbn"}},
+		{name: "optional behavior", rule: get("/v1/shelves/{shelf}"), required: []string{"book_id", "shelf"}},
+		{name: "every binding", rule: get("/v1/isbn/{isbn}", get("/v1/{shelf}/{isbn}")), required: []string{"book_id", "isbn"}},
+		{name: "some bindings", rule: get("/v1/isbn/{isbn}", get("/v1/books")), required: []string{"book_id"}},
+		{name: "grpc backend", rule: get("/v1/isbn/{isbn}"), backend: mcpannotations.MCPBackend_MCP_BACKEND_GRPC, required: []string{"book_id"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := testRequestFile()
+			file.Service = []*descriptorpb.ServiceDescriptorProto{testService("Get", ".test.Request", test.rule, &mcpannotations.MCPToolOptions{Backend: test.backend})}
+			methods, err := extractMCPMethods(newTestPlugin(t, file))
+			if err != nil {
+				t.Fatalf("extractMCPMethods failed: %v", err)
+			}
+
+			method := methods[0]
+			if !reflect.DeepEqual(method.InputSchema.Required, test.required) {
+				t.Errorf("required = %q, want %q", method.InputSchema.Required, test.required)
+			}
+			for _, param := range method.Parameters {
+				if want := method.InputSchema.IsRequired(param.Name); param.Required != want {
+					t.Errorf("parameter %s required = %t, want %t", param.Name, param.Required, want)
+				}
+			}
+		})
+	}
+}
+
+// testRequestMessage returns the Request message of testRequestFile.
+func testRequestMessage(t *testing.T) *protogen.Message {
+	t.Helper()
+	return newTestPlugin(t, testRequestFile()).Files[0].Messages[0]
+}
+
+// testRequestFile builds test.proto, with a request message holding scalar,
+// optional, repeated, hidden and nested message fields:
+//
+//	message Request {
+//	  string book_id = 1;
+//	  Book book = 2;
+//	  repeated string tags = 3;
+//	  string secret = 4 [(mcp.v1.field) = { hidden: true }];
+//	  optional string isbn = 5;
+//	  string shelf = 6 [(google.api.field_behavior) = OPTIONAL];
+//	}
+//	message Book {
+//	  string shelf_name = 1;
+//	  string etag = 2 [(google.api.field_behavior) = OUTPUT_ONLY];
+//	}
+func testRequestFile() *descriptorpb.FileDescriptorProto {
+	secret := testField("secret", 4, optional, stringType, "")
+	secret.Options = &descriptorpb.FieldOptions{}
+	proto.SetExtension(secret.Options, mcpannotations.E_Field, &mcpannotations.MCPFieldOptions{Hidden: true})
+	isbn := testField("isbn", 5, optional, stringType, "")
+	isbn.Proto3Optional = proto.Bool(true)
+	isbn.OneofIndex = proto.Int32(0)
+	shelf := testField("shelf", 6, optional, stringType, "")
+	shelf.Options = &descriptorpb.FieldOptions{}
+	proto.SetExtension(shelf.Options, httpannotations.E_FieldBehavior, []httpannotations.FieldBehavior{httpannotations.FieldBehavior_OPTIONAL})
+	etag := testField("etag", 2, optional, stringType, "")
+	etag.Options = &descriptorpb.FieldOptions{}
+	proto.SetExtension(etag.Options, httpannotations.E_FieldBehavior, []httpannotations.FieldBehavior{httpannotations.FieldBehavior_OUTPUT_ONLY})
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Request"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					testField("book_id", 1, optional, stringType, ""),
+					testField("book", 2, optional, messageType, ".test.Book"),
+					testField("tags", 3, repeated, stringType, ""),
+					secret,
+					isbn,
+					shelf,
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_isbn")}},
+			},
+			{
+				Name: proto.String("Book"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					testField("shelf_name", 1, optional, stringType, ""),
+					etag,
+				},
+			},
+		},
+	}
+}
+
+// Labels and types for testField
+const (
+	optional    = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated    = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	stringType  = descriptorpb.FieldDescriptorProto_TYPE_STRING
+	messageType = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+)
+
+// testField builds a field descriptor whose JSON name is its proto name.
+func testField(name string, number int32, label descriptorpb.FieldDescriptorProto_Label, kind descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    label.Enum(),
+		Type:     kind.Enum(),
+		JsonName: proto.String(name),
+	}
+	if typeName != "" {
+		field.TypeName = proto.String(typeName)
+	}
+	return field
+}
+
+// testMessage builds a message descriptor.
+func testMessage(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+	return &descriptorpb.DescriptorProto{Name: proto.String(name), Field: fields}
+}
+
+// testToolFile builds tool.proto with the given messages and a Find method,
+// exposed as the "find" tool, taking and returning the first of them.
+func testToolFile(rule *httpannotations.HttpRule, messages ...*descriptorpb.DescriptorProto) *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("tool.proto"),
+		Package:     proto.String("tool"),
+		Syntax:      proto.String("proto3"),
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/tool")},
+		MessageType: messages,
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			testService("Find", ".tool."+messages[0].GetName(), rule, &mcpannotations.MCPToolOptions{}),
+		},
+	}
+}
+
+// testService builds a service exposing one method, taking and returning
+// the given message, as a tool with the given options.
+func testService(method, message string, rule *httpannotations.HttpRule, toolOptions *mcpannotations.MCPToolOptions) *descriptorpb.ServiceDescriptorProto {
+	methodOptions := &descriptorpb.MethodOptions{}
+	if rule != nil {
+		proto.SetExtension(methodOptions, httpannotations.E_Http, rule)
+	}
+	toolOptions.Enabled = proto.Bool(true)
+	proto.SetExtension(methodOptions, mcpannotations.E_Tool, toolOptions)
+
+	return &descriptorpb.ServiceDescriptorProto{
+		Name: proto.String("TestService"),
+		Method: []*descriptorpb.MethodDescriptorProto{{
+			Name:       proto.String(method),
+			InputType:  proto.String(message),
+			OutputType: proto.String(message),
+			Options:    methodOptions,
+		}},
+	}
+}
+
+// newTestPlugin runs protogen over files, listed dependencies first, and
+// generates the last of them.
+func newTestPlugin(t *testing.T, files ...*descriptorpb.FileDescriptorProto) *protogen.Plugin {
+	t.Helper()
+
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{files[len(files)-1].GetName()},
+		ProtoFile:      files,
+	})
+	if err != nil {
+		t.Fatalf("protogen.Options.New failed: %v", err)
+	}
+	return gen
+}
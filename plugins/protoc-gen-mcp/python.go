@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pythonIdentifier matches characters that are valid in MCP tool names but not in Python identifiers
+var pythonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// pythonKeywords cannot name a function or a parameter.
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// pythonReserved are the builtins and module-level names the generated server
+// relies on, which a tool function such as "list" or "json" would shadow.
+var pythonReserved = map[string]bool{
+	"Exception": true, "ValueError": true, "bool": true, "dict": true, "hasattr": true,
+	"int": true, "isinstance": true, "len": true, "list": true, "str": true, "type": true,
+
+	"API_BASE": true, "VERIFY_SSL": true, "GRPC_TARGET": true, "FILE_DESCRIPTOR_SET": true,
+	"DESCRIPTOR_POOL": true, "Annotated": true, "Any": true, "Literal": true, "Optional": true,
+	"Union": true, "FastMCP": true, "ToolAnnotations": true, "Field": true, "Required": true,
+	"TypedDict": true, "os": true, "sys": true, "json": true, "re": true, "quote": true,
+	"base64": true, "httpx": true, "grpc": true, "descriptor_pb2": true, "descriptor_pool": true,
+	"json_format": true, "message_factory": true, "mcp": true, "make_api_request": true,
+	"load_descriptor_pool": true, "make_grpc_request": true, "find_oneof_conflicts": true,
+	"path_value": true, "nested_value": true, "add_query_param": true,
+}
+
+// pythonToolBodyNames are the module-level names the body of a tool function
+// reads, which an argument of the same name would shadow. Annotations are
+// evaluated in module scope, so arguments such as "type" or "list" are fine.
+var pythonToolBodyNames = map[string]bool{
+	"Exception": true, "json": true, "API_BASE": true, "GRPC_TARGET": true,
+	"make_api_request": true, "make_grpc_request": true, "find_oneof_conflicts": true,
+	"path_value": true, "nested_value": true, "add_query_param": true,
+}
+
+// pythonFunctionName returns the function defining a tool. Characters Python
+// does not allow become "_", a leading digit gets a "_" prefix and reserved
+// names a "_" suffix, so "3d-lookup" becomes _3d_lookup and "list" list_.
+func pythonFunctionName(toolName string) string {
+	name := pythonIdentifier.ReplaceAllString(toolName, "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	if pythonKeywords[name] || pythonReserved[name] {
+		name += "_"
+	}
+	return name
+}
+
+// checkPythonArguments rejects tool arguments the generated function cannot
+// take as keyword parameters: keywords such as "from" do not compile, and
+// names the tool body reads, such as "json", would break it. They are not
+// renamed, since FastMCP passes arguments by the name of the parameter.
+// Names starting with "_" are refused by FastMCP and kept for locals.
+func checkPythonArguments(method *MCPMethod) error {
+	for _, param := range method.Parameters {
+		if pythonKeywords[param.Name] || pythonToolBodyNames[param.Name] || strings.HasPrefix(param.Name, "_") {
+			return fmt.Errorf("tool %q: argument %q cannot be a Python parameter, since it is a keyword, a name the tool body uses or starts with \"_\": rename the field", method.ToolName, param.Name)
+		}
+	}
+	return nil
+}
+
+// pythonTypeName returns the variable holding a message definition's
+// TypedDict. The suffix keeps messages such as Field or Any from shadowing
+// the module's imports; the TypedDict itself keeps the message name, which
+// pydantic uses in the tool's input schema.
+func pythonTypeName(name string) string {
+	return name + "Type"
+}
+
+// pythonType renders the Python type hint for a schema. Message references
+// are quoted so definitions can refer to each other in any order.
+func pythonType(schema *JSONSchema) string {
+	if schema.Ref != "" {
+		return strconv.Quote(pythonTypeName(schema.RefName()))
+	}
+
+	if len(schema.AnyOf) > 0 {
+		var members []string
+		nullable := false
+		for _, member := range schema.AnyOf {
+			if member.Type == "null" {
+				nullable = true
+				continue
+			}
+			members = append(members, pythonAnnotation(member, false))
+		}
+		typeHint := members[0]
+		if len(members) > 1 {
+			typeHint = "Union[" + strings.Join(members, ", ") + "]"
+		}
+		if nullable {
+			typeHint = "Optional[" + typeHint + "]"
+		}
+		return typeHint
+	}
+
+	if len(schema.Enum) > 0 {
+		values := make([]string, len(schema.Enum))
+		for i, value := range schema.Enum {
+			values[i] = pythonLiteral(value)
+		}
+		return "Literal[" + strings.Join(values, ", ") + "]"
+	}
+
+	switch schema.Type {
+	case "string":
+		return "str"
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "null":
+		return "None"
+	case "array":
+		if schema.Items != nil {
+			return "list[" + pythonAnnotation(schema.Items, false) + "]"
+		}
+		return "list"
+	case "object":
+		if schema.AdditionalProperties != nil {
+			keyType := "str"
+			if schema.PropertyNames != nil {
+				keyType = pythonAnnotation(schema.PropertyNames, false)
+			}
+			return "dict[" + keyType + ", " + pythonAnnotation(schema.AdditionalProperties, false) + "]"
+		}
+		return "dict[str, Any]"
+	default:
+		return "Any"
+	}
+}
+
+// pythonAnnotation renders a type hint carrying the schema's description and
+// examples as pydantic Field metadata, so they reach the tool's input schema.
+func pythonAnnotation(schema *JSONSchema, optional bool) string {
+	typeHint := pythonType(schema)
+	if optional && !schema.IsNullable() {
+		typeHint = "Optional[" + typeHint + "]"
+	}
+
+	var args []string
+	if schema.Description != "" {
+		args = append(args, "description="+strconv.Quote(schema.Description))
+	}
+	if len(schema.Examples) > 0 {
+		args = append(args, "examples="+pythonLiteral(schema.Examples))
+	}
+	if schema.Minimum != nil {
+		args = append(args, fmt.Sprintf("ge=%d", *schema.Minimum))
+	}
+	if schema.Maximum != nil {
+		args = append(args, fmt.Sprintf("le=%d", *schema.Maximum))
+	}
+	if schema.Pattern != "" {
+		args = append(args, "pattern="+strconv.Quote(schema.Pattern))
+	}
+
+	// Keywords pydantic has no parameter for are passed through verbatim
+	extra := map[string]any{}
+	if schema.Format != "" && schema.Type == "string" {
+		extra["format"] = schema.Format
+	}
+	if schema.Encoding != "" {
+		extra["contentEncoding"] = schema.Encoding
+	}
+	if schema.Type == "object" && len(schema.Properties) > 0 {
+		// Inline objects such as google.protobuf.Any keep their declared keys
+		properties := map[string]any{}
+		for _, prop := range schema.Properties {
+			properties[prop.Name] = schemaValue(prop.Schema)
+		}
+		extra["properties"] = properties
+		if len(schema.Required) > 0 {
+			extra["required"] = toAnySlice(schema.Required)
+		}
+	}
+	if len(extra) > 0 {
+		args = append(args, "json_schema_extra="+pythonLiteral(extra))
+	}
+	if len(args) == 0 {
+		return typeHint
+	}
+	return fmt.Sprintf("Annotated[%s, Field(%s)]", typeHint, strings.Join(args, ", "))
+}
+
+// pythonParam renders a tool function parameter's annotation and default.
+func pythonParam(param *MCPParameter) string {
+	if param.Required {
+		return pythonAnnotation(param.Schema, false)
+	}
+	return pythonAnnotation(param.Schema, true) + " = None"
+}
+
+// pythonLiteral renders a JSON value as a Python literal.
+func pythonLiteral(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case string:
+		return strconv.Quote(v)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = pythonLiteral(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			items[i] = strconv.Quote(key) + ": " + pythonLiteral(v[key])
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// schemaValue converts a schema to generic JSON values for pythonLiteral.
+func schemaValue(schema *JSONSchema) any {
+	data, _ := json.Marshal(schema)
+	var value any
+	_ = json.Unmarshal(data, &value)
+	return value
+}
+
+func toAnySlice(values []string) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// pythonOneofGroups renders oneof constraints as the [path, name, members]
+// list consumed by find_oneof_conflicts.
+func pythonOneofGroups(oneofs []*OneofConstraint) string {
+	var groups []any
+	for _, oneof := range oneofs {
+		groups = append(groups, []any{toAnySlice(oneof.Path), oneof.Name, toAnySlice(oneof.Fields)})
+	}
+	return pythonLiteral(groups)
+}
+
+// pythonCondition renders the check that a binding's path variables are set.
+func pythonCondition(binding *HTTPInfo) string {
+	var conditions []string
+	for _, variable := range binding.Template.Variables() {
+		conditions = append(conditions, pythonPathVariable(variable)+" is not None")
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// pythonPath renders a path template as a Python expression that expands
+// its variables from the tool arguments.
+func pythonPath(template *PathTemplate) string {
+	var parts []string
+	literal := ""
+	for _, segment := range template.Segments {
+		literal += "/"
+		if segment.Variable == nil {
+			literal += segment.Literal
+			continue
+		}
+
+		parts = append(parts, strconv.Quote(literal))
+		literal = ""
+
+		variable := segment.Variable
+		pattern := "None"
+		if variable.Pattern() != "" {
+			pattern = strconv.Quote(variable.Pattern())
+		}
+		multiSegment := "False"
+		if variable.MultiSegment() {
+			multiSegment = "True"
+		}
+		parts = append(parts, fmt.Sprintf("path_value(%s, %s, %s, %s)",
+			strconv.Quote(strings.Join(variable.Names, ".")), pythonPathVariable(variable), pattern, multiSegment))
+	}
+	if template.Verb != "" {
+		literal += ":" + template.Verb
+	}
+	if literal != "" {
+		parts = append(parts, strconv.Quote(literal))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// pythonPathVariable renders the expression reading a path variable's value:
+// the argument itself, or a lookup into a message argument.
+func pythonPathVariable(variable *PathVariable) string {
+	if len(variable.Names) == 1 {
+		return variable.Names[0]
+	}
+	keys := toAnySlice(variable.Names[1:])
+	return fmt.Sprintf("nested_value(%s, %s)", variable.Names[0], pythonLiteral(keys))
+}
@@ -0,0 +1,489 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	mcpannotations "proto-to-mcp-tutorial/generated/go/mcp/protobuf"
+
+	httpannotations "google.golang.org/genproto/googleapis/api/annotations"
+)
+
+// pythonStubs stand in for the generated server's dependencies, which only
+// need to import for tools to be called directly. The httpx client answers
+// every request with a description of the request itself.
+var pythonStubs = map[string]string{
+	"httpx.py": `class HTTPStatusError(Exception):
+    pass
+
+
+class Response:
+    status_code = 200
+    headers = {}
+    content = b"{}"
+
+    def __init__(self, request):
+        self.request = request
+
+    def raise_for_status(self):
+        pass
+
+    def json(self):
+        return self.request
+
+
+class AsyncClient:
+    def __init__(self, **kwargs):
+        pass
+
+    async def __aenter__(self):
+        return self
+
+    async def __aexit__(self, *args):
+        pass
+
+    async def request(self, method, url, headers=None, params=None, json=None, timeout=None):
+        return Response({"method": method, "url": url, "params": params, "json": json})
+`,
+	"pydantic.py":            "def Field(**kwargs):\n    return kwargs\n",
+	"mcp/__init__.py":        "",
+	"mcp/types.py":           "class ToolAnnotations:\n    def __init__(self, **kwargs):\n        pass\n",
+	"mcp/server/__init__.py": "",
+	"mcp/server/fastmcp.py": `class FastMCP:
+    def __init__(self, name, instructions=None):
+        self.tools = {}
+
+    def tool(self, name, **kwargs):
+        def register(fn):
+            self.tools[name] = fn
+            return fn
+        return register
+`,
+}
+
+// pythonCallTool loads the server module and prints the result of calling a
+// tool with the JSON object of keyword arguments given on the command line.
+const pythonCallTool = `import asyncio, importlib.util, json, sys
+spec = importlib.util.spec_from_file_location("mcp_server", sys.argv[1])
+server = importlib.util.module_from_spec(spec)
+spec.loader.exec_module(server)
+print(asyncio.run(server.mcp.tools[sys.argv[2]](**json.loads(sys.argv[3]))))
+`
+
+// callPythonTool runs a generated server against pythonStubs and returns the
+// result of calling the tool with a JSON object of keyword arguments.
+func callPythonTool(t *testing.T, server, tool, arguments string) string {
+	t.Helper()
+
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not installed")
+	}
+
+	dir := t.TempDir()
+	for name, content := range pythonStubs {
+		path := filepath.Join(dir, "stubs", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	serverPath := filepath.Join(dir, "mcp_server.py")
+	if err := os.WriteFile(serverPath, []byte(server), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(python, "-c", pythonCallTool, serverPath, tool, arguments)
+	cmd.Env = append(os.Environ(), "PYTHONPATH="+filepath.Join(dir, "stubs"))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("calling the tool failed: %v\n%s", err, output)
+	}
+	return string(output)
+}
+
+// pythonRequest is the HTTP request a tool made, as echoed by the httpx stub.
+type pythonRequest struct {
+	Method string     `json:"method"`
+	URL    string     `json:"url"`
+	Params [][]string `json:"params"`
+	JSON   any        `json:"json"`
+}
+
+// callPythonHTTPTool calls an HTTP tool and returns the request it made.
+func callPythonHTTPTool(t *testing.T, server, tool, arguments string) *pythonRequest {
+	t.Helper()
+
+	output := callPythonTool(t, server, tool, arguments)
+	request := &pythonRequest{}
+	if err := json.Unmarshal([]byte(output), request); err != nil || request.URL == "" {
+		t.Fatalf("tool %s did not make a request: %s", tool, output)
+	}
+	return request
+}
+
+func TestPythonOneofConflict(t *testing.T) {
+	output := callPythonTool(t, generateTestPythonServer(t, testRichFile()), "rich_move_it", `{"id": "b1", "shelf": "s", "room": "r"}`)
+
+	want := `"error": "Only one of shelf, room may be set for oneof 'destination', got shelf, room"`
+	if !strings.Contains(output, want) {
+		t.Errorf("tool result = %s, want it to contain %s", output, want)
+	}
+}
+
+func TestPythonArgumentsNamedLikeBuiltins(t *testing.T) {
+	file := testToolFile(&httpannotations.HttpRule{Pattern: &httpannotations.HttpRule_Get{Get: "/v1/things/{type}"}},
+		testMessage("Request", testField("type", 1, optional, stringType, ""), testField("list", 2, repeated, stringType, "")))
+
+	request := callPythonHTTPTool(t, generateTestPythonServer(t, file), "find", `{"type": "a b", "list": ["x", "y"]}`)
+	if want := "http://localhost:8080/v1/things/a%20b"; request.URL != want {
+		t.Errorf("url = %s, want %s", request.URL, want)
+	}
+	if want := [][]string{{"list", "x"}, {"list", "y"}}; !reflect.DeepEqual(request.Params, want) {
+		t.Errorf("params = %q, want %q", request.Params, want)
+	}
+}
+
+func TestPythonBindingChoice(t *testing.T) {
+	get := func(path string, additional ...*httpannotations.HttpRule) *httpannotations.HttpRule {
+		return &httpannotations.HttpRule{Pattern: &httpannotations.HttpRule_Get{Get: path}, AdditionalBindings: additional}
+	}
+	request := testMessage("Request", testField("book_id", 1, optional, stringType, ""), testField("shelf", 2, optional, stringType, ""))
+
+	tests := []struct {
+		name      string
+		rule      *httpannotations.HttpRule
+		arguments string
+		url       string // Requested URL, or empty when no binding matches
+	}{
+		{
+			name:      "most specific binding",
+			rule:      get("/v1/books/{book_id}", get("/v1/shelves/{shelf}/books/{book_id}"), get("/v1/books")),
+			arguments: `{"book_id": "b1", "shelf": "s1"}`,
+			url:       "/v1/shelves/s1/books/b1",
+		},
+		{
+			name:      "fewer path parameters",
+			rule:      get("/v1/books/{book_id}", get("/v1/shelves/{shelf}/books/{book_id}"), get("/v1/books")),
+			arguments: `{"book_id": "b1"}`,
+			url:       "/v1/books/b1",
+		},
+		{
+			name:      "binding without path parameters",
+			rule:      get("/v1/books/{book_id}", get("/v1/shelves/{shelf}/books/{book_id}"), get("/v1/books")),
+			arguments: `{}`,
+			url:       "/v1/books",
+		},
+		{
+			name:      "unused path parameter in the query",
+			rule:      get("/v1/books/{book_id}", get("/v1/shelves/{shelf}")),
+			arguments: `{"shelf": "s1"}`,
+			url:       "/v1/shelves/s1",
+		},
+		{
+			name:      "no binding matches",
+			rule:      get("/v1/books/{book_id}", get("/v1/shelves/{shelf}")),
+			arguments: `{}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := generateTestPythonServer(t, testToolFile(test.rule, request))
+
+			if test.url == "" {
+				output := callPythonTool(t, server, "find", test.arguments)
+				if want := "No HTTP binding matches the supplied arguments"; !strings.Contains(output, want) {
+					t.Errorf("tool result = %s, want it to contain %q", output, want)
+				}
+				return
+			}
+
+			got := callPythonHTTPTool(t, server, "find", test.arguments)
+			if want := "http://localhost:8080" + test.url; got.URL != want {
+				t.Errorf("url = %s, want %s", got.URL, want)
+			}
+			if len(got.Params) != 0 {
+				t.Errorf("params = %q, want none", got.Params)
+			}
+		})
+	}
+}
+
+func TestPythonSplitBindings(t *testing.T) {
+	defer func(mode string) { *additionalBindings = mode }(*additionalBindings)
+	*additionalBindings = "split"
+
+	rule := &httpannotations.HttpRule{
+		Pattern:            &httpannotations.HttpRule_Get{Get: "/v1/books/{book_id}"},
+		AdditionalBindings: []*httpannotations.HttpRule{{Pattern: &httpannotations.HttpRule_Get{Get: "/v1/shelves/{shelf}/books/{book_id}"}}},
+	}
+	file := testToolFile(rule, testMessage("Request", testField("book_id", 1, optional, stringType, ""), testField("shelf", 2, optional, stringType, "")))
+	server := generateTestPythonServer(t, file)
+
+	tests := []struct {
+		tool   string
+		url    string
+		params [][]string
+	}{
+		{tool: "find", url: "http://localhost:8080/v1/books/b1", params: [][]string{{"shelf", "s1"}}},
+		{tool: "find_alt1", url: "http://localhost:8080/v1/shelves/s1/books/b1", params: [][]string{}},
+	}
+	for _, test := range tests {
+		got := callPythonHTTPTool(t, server, test.tool, `{"book_id": "b1", "shelf": "s1"}`)
+		if got.URL != test.url || !reflect.DeepEqual(got.Params, test.params) {
+			t.Errorf("%s requested %s with params %q, want %s with %q", test.tool, got.URL, got.Params, test.url, test.params)
+		}
+	}
+}
+
+// testBodyMessages builds a request with a path field, a message field to
+// select as the body, a hidden field and a field left for the query string:
+//
+//	message Request {
+//	  string book_id = 1;
+//	  Book book = 2;
+//	  string note = 3;
+//	  string secret = 4 [(mcp.v1.field) = { hidden: true }];
+//	}
+//	message Book { string title = 1; }
+func testBodyMessages() []*descriptorpb.DescriptorProto {
+	secret := testField("secret", 4, optional, stringType, "")
+	secret.Options = &descriptorpb.FieldOptions{}
+	proto.SetExtension(secret.Options, mcpannotations.E_Field, &mcpannotations.MCPFieldOptions{Hidden: true})
+
+	return []*descriptorpb.DescriptorProto{
+		testMessage("Request",
+			testField("book_id", 1, optional, stringType, ""),
+			testField("book", 2, optional, messageType, ".tool.Book"),
+			testField("note", 3, optional, stringType, ""),
+			secret),
+		testMessage("Book", testField("title", 1, optional, stringType, "")),
+	}
+}
+
+func TestPythonBodySelector(t *testing.T) {
+	rule := &httpannotations.HttpRule{Pattern: &httpannotations.HttpRule_Post{Post: "/v1/books/{book_id}"}, Body: "book"}
+	server := generateTestPythonServer(t, testToolFile(rule, testBodyMessages()...))
+
+	got := callPythonHTTPTool(t, server, "find", `{"book_id": "b1", "book": {"title": "Dune"}, "note": "signed"}`)
+	if want := "http://localhost:8080/v1/books/b1"; got.URL != want {
+		t.Errorf("url = %s, want %s", got.URL, want)
+	}
+	if want := map[string]any{"title": "Dune"}; !reflect.DeepEqual(got.JSON, want) {
+		t.Errorf("body = %v, want %v", got.JSON, want)
+	}
+	if want := [][]string{{"note", "signed"}}; !reflect.DeepEqual(got.Params, want) {
+		t.Errorf("params = %q, want %q", got.Params, want)
+	}
+}
+
+func TestPythonResponseBody(t *testing.T) {
+	rule := &httpannotations.HttpRule{Pattern: &httpannotations.HttpRule_Get{Get: "/v1/books/{book_id}"}, ResponseBody: "book"}
+	server := generateTestPythonServer(t, testToolFile(rule, testBodyMessages()...))
+
+	// The gateway replies with the book alone, which the tool puts back under
+	// its key; the httpx stub replies with the request
+	output := callPythonTool(t, server, "find", `{"book_id": "b1", "note": "signed"}`)
+	var result map[string]*pythonRequest
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("tool result is not JSON: %s", output)
+	}
+	if got := result["book"]; got == nil || got.URL != "http://localhost:8080/v1/books/b1" {
+		t.Errorf("tool result = %s, want the response under \"book\"", output)
+	}
+}
+
+// testQueryMessages builds a request whose fields exercise every query
+// string encoding rule:
+//
+//	message Request {
+//	  Book book = 1;
+//	  bool archived = 2;
+//	  repeated string ids = 3;
+//	  map<string, string> filters = 4;
+//	}
+//	message Book { string title = 1; map<string, string> labels = 2; Shelf shelf = 3; }
+//	message Shelf { string name = 1; }
+func testQueryMessages() []*descriptorpb.DescriptorProto {
+	mapEntry := func(name string) *descriptorpb.DescriptorProto {
+		entry := testMessage(name, testField("key", 1, optional, stringType, ""), testField("value", 2, optional, stringType, ""))
+		entry.Options = &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)}
+		return entry
+	}
+
+	request := testMessage("Request",
+		testField("book", 1, optional, messageType, ".tool.Book"),
+		testField("archived", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_BOOL, ""),
+		testField("ids", 3, repeated, stringType, ""),
+		testField("filters", 4, repeated, messageType, ".tool.Request.FiltersEntry"))
+	request.NestedType = []*descriptorpb.DescriptorProto{mapEntry("FiltersEntry")}
+	book := testMessage("Book",
+		testField("title", 1, optional, stringType, ""),
+		testField("labels", 2, repeated, messageType, ".tool.Book.LabelsEntry"),
+		testField("shelf", 3, optional, messageType, ".tool.Shelf"))
+	book.NestedType = []*descriptorpb.DescriptorProto{mapEntry("LabelsEntry")}
+
+	return []*descriptorpb.DescriptorProto{request, book, testMessage("Shelf", testField("name", 1, optional, stringType, ""))}
+}
+
+// testQueryParams are the query pairs grpc-gateway expects for
+// testQueryArguments.
+var (
+	testQueryArguments = `{"book": {"title": "Dune", "labels": {"lang": "en"}, "shelf": {"name": "s1"}}, "archived": true, "ids": ["a", "b"], "filters": {"genre": "sf"}}`
+	testQueryParams    = [][]string{
+		{"book.title", "Dune"},
+		{"book.labels[lang]", "en"},
+		{"book.shelf.name", "s1"},
+		{"archived", "true"},
+		{"ids", "a"},
+		{"ids", "b"},
+		{"filters[genre]", "sf"},
+	}
+)
+
+func TestPythonQueryParams(t *testing.T) {
+	rule := &httpannotations.HttpRule{Pattern: &httpannotations.HttpRule_Get{Get: "/v1/books"}}
+	server := generateTestPythonServer(t, testToolFile(rule, testQueryMessages()...))
+
+	got := callPythonHTTPTool(t, server, "find", testQueryArguments)
+	if !reflect.DeepEqual(got.Params, testQueryParams) {
+		t.Errorf("params = %q, want %q", got.Params, testQueryParams)
+	}
+}
+
+func TestPythonGRPCTarget(t *testing.T) {
+	defer func(target string) { *grpcTarget = target }(*grpcTarget)
+	*grpcTarget = "books.internal:9090"
+
+	file := testRequestFile()
+	file.Service = []*descriptorpb.ServiceDescriptorProto{testService("Get", ".test.Request", nil, &mcpannotations.MCPToolOptions{Backend: mcpannotations.MCPBackend_MCP_BACKEND_GRPC})}
+	server := generateTestPythonServer(t, file)
+
+	if want := `GRPC_TARGET = "books.internal:9090"`; !strings.Contains(server, want) {
+		t.Errorf("generated server does not contain %s", want)
+	}
+}
+
+func TestPythonFunctionName(t *testing.T) {
+	tests := map[string]string{
+		"get_book":    "get_book",
+		"get-book.v2": "get_book_v2",
+		"3d-lookup":   "_3d_lookup",
+		"import":      "import_",
+		"list":        "list_",
+		"json":        "json_",
+		"path_value":  "path_value_",
+	}
+
+	for toolName, want := range tests {
+		if got := pythonFunctionName(toolName); got != want {
+			t.Errorf("pythonFunctionName(%q) = %q, want %q", toolName, got, want)
+		}
+	}
+}
+
+func TestCheckPythonArguments(t *testing.T) {
+	tests := map[string]bool{
+		"book_id":  true,
+		"bookId":   true,
+		"type":     true,
+		"list":     true,
+		"Optional": true,
+		"from":     false,
+		"json":     false,
+		"API_BASE": false,
+		"_url":     false,
+	}
+
+	for name, valid := range tests {
+		method := &MCPMethod{ToolName: "do_it", Parameters: []*MCPParameter{{Name: name}}}
+		if err := checkPythonArguments(method); (err == nil) != valid {
+			t.Errorf("checkPythonArguments(%q) error = %v, want valid %t", name, err, valid)
+		}
+	}
+}
+
+// testRichFile builds rich.proto:
+//
+//	service Rich {
+//	  option (mcp.v1.service) = { expose_all: true tool_prefix: "rich_" };
+//	  rpc MoveIt(MoveRequest) returns (MoveRequest) {
+//	    option (google.api.http) = { post: "/v1/{id}:move" body: "*" };
+//	  }
+//	}
+//	message MoveRequest {
+//	  string id = 1;
+//	  oneof destination { string shelf = 2; string room = 3; }
+//	}
+func testRichFile() *descriptorpb.FileDescriptorProto {
+	serviceOptions := &descriptorpb.ServiceOptions{}
+	proto.SetExtension(serviceOptions, mcpannotations.E_Service, &mcpannotations.MCPServiceOptions{ExposeAll: true, ToolPrefix: "rich_"})
+	methodOptions := &descriptorpb.MethodOptions{}
+	proto.SetExtension(methodOptions, httpannotations.E_Http, &httpannotations.HttpRule{
+		Pattern: &httpannotations.HttpRule_Post{Post: "/v1/{id}:move"},
+		Body:    "*",
+	})
+
+	shelf := testField("shelf", 2, optional, stringType, "")
+	shelf.OneofIndex = proto.Int32(0)
+	room := testField("room", 3, optional, stringType, "")
+	room.OneofIndex = proto.Int32(0)
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("rich.proto"),
+		Package: proto.String("rich.v1"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/rich")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name:      proto.String("MoveRequest"),
+			Field:     []*descriptorpb.FieldDescriptorProto{testField("id", 1, optional, stringType, ""), shelf, room},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("destination")}},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name:    proto.String("Rich"),
+			Options: serviceOptions,
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("MoveIt"),
+				InputType:  proto.String(".rich.v1.MoveRequest"),
+				OutputType: proto.String(".rich.v1.MoveRequest"),
+				Options:    methodOptions,
+			}},
+		}},
+	}
+}
+
+// generateTestPythonServer renders the python target for the last of files,
+// which are listed dependencies first.
+func generateTestPythonServer(t *testing.T, files ...*descriptorpb.FileDescriptorProto) string {
+	t.Helper()
+
+	gen := newTestPlugin(t, files...)
+	methods, err := extractMCPMethods(gen)
+	if err != nil {
+		t.Fatalf("extractMCPMethods failed: %v", err)
+	}
+	server, err := extractMCPServer(gen, methods)
+	if err != nil {
+		t.Fatalf("extractMCPServer failed: %v", err)
+	}
+	if err := generateMCPServer(gen, server); err != nil {
+		t.Fatalf("generateMCPServer failed: %v", err)
+	}
+
+	response := gen.Response()
+	if response.Error != nil {
+		t.Fatalf("generation failed: %s", response.GetError())
+	}
+	return response.File[0].GetContent()
+}
@@ -0,0 +1,464 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	httpannotations "google.golang.org/genproto/googleapis/api/annotations"
+)
+
+// JSONSchema is the subset of JSON Schema (draft 2020-12) used to describe
+// tool inputs and outputs.
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	Examples             []any                  `json:"examples,omitempty"`
+	ReadOnly             bool                   `json:"readOnly,omitempty"`
+	Minimum              *int64                 `json:"minimum,omitempty"`
+	Maximum              *int64                 `json:"maximum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Encoding             string                 `json:"contentEncoding,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           Properties             `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	PropertyNames        *JSONSchema            `json:"propertyNames,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+	AllOf                []*JSONSchema          `json:"allOf,omitempty"`
+	Not                  *JSONSchema            `json:"not,omitempty"`
+	Defs                 map[string]*JSONSchema `json:"$defs,omitempty"`
+
+	// Oneofs lists the object's proto oneof groups; OneOf/AllOf carry the
+	// equivalent JSON Schema constraint
+	Oneofs []*OneofGroup `json:"-"`
+}
+
+// OneofGroup is a proto oneof: at most one of its fields may be set.
+type OneofGroup struct {
+	Name   string
+	Fields []string
+}
+
+// Property is a named entry of a JSON Schema "properties" object.
+type Property struct {
+	Name   string
+	Schema *JSONSchema
+}
+
+// Properties keeps object properties in proto field order when marshaled.
+type Properties []*Property
+
+// Get returns the schema of the named property, or nil.
+func (p Properties) Get(name string) *JSONSchema {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Schema
+		}
+	}
+	return nil
+}
+
+func (p Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(prop.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(prop.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// SchemaDef is a named message definition shared by one or more tools.
+type SchemaDef struct {
+	Name   string
+	Schema *JSONSchema
+}
+
+// SortedDefs returns the schema's $defs ordered by name.
+func (s *JSONSchema) SortedDefs() []*SchemaDef {
+	var defs []*SchemaDef
+	for name, def := range s.Defs {
+		defs = append(defs, &SchemaDef{Name: name, Schema: def})
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// IsRequired reports whether the named property is listed as required.
+func (s *JSONSchema) IsRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNullable reports whether the schema accepts JSON null.
+func (s *JSONSchema) IsNullable() bool {
+	for _, member := range s.AnyOf {
+		if member.Type == "null" {
+			return true
+		}
+	}
+	return s.Type == "null"
+}
+
+// RefName returns the $defs name a "$ref" points at.
+func (s *JSONSchema) RefName() string {
+	return strings.TrimPrefix(s.Ref, "#/$defs/")
+}
+
+// schemaGenerator builds JSON Schemas for messages. Nested messages are
+// emitted once under "$defs" and referenced with "$ref", which also covers
+// recursive and imported message types. Definition names are shared across
+// all tools of a server so generated types line up.
+type schemaGenerator struct {
+	names map[protoreflect.FullName]string
+	taken map[string]bool
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		names: map[protoreflect.FullName]string{},
+		taken: map[string]bool{},
+	}
+}
+
+// messageSchema returns the schema of a request (output false) or response
+// (output true) message, with every referenced message under "$defs".
+func (g *schemaGenerator) messageSchema(message *protogen.Message, output bool) *JSONSchema {
+	defs := map[string]*JSONSchema{}
+	schema := g.objectSchema(message, output, defs)
+	if len(defs) > 0 {
+		schema.Defs = defs
+	}
+	return schema
+}
+
+func (g *schemaGenerator) objectSchema(message *protogen.Message, output bool, defs map[string]*JSONSchema) *JSONSchema {
+	schema := &JSONSchema{Type: "object"}
+	if message == nil {
+		return schema
+	}
+
+	for _, field := range message.Fields {
+		if !isFieldExposed(field, output) {
+			continue
+		}
+
+		fieldSchema := g.fieldSchema(field, output, defs)
+		fieldSchema.Description = joinDescriptions(extractFieldDescription(field), fieldSchema.Description)
+		fieldSchema.Examples = fieldExamples(field, fieldSchema)
+		fieldSchema.ReadOnly = output && hasFieldBehavior(field, httpannotations.FieldBehavior_OUTPUT_ONLY)
+
+		name := fieldName(field)
+		schema.Properties = append(schema.Properties, &Property{Name: name, Schema: fieldSchema})
+		if !output && isFieldRequired(field) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	for _, oneof := range message.Oneofs {
+		if oneof.Desc.IsSynthetic() {
+			continue
+		}
+		group := &OneofGroup{Name: string(oneof.Desc.Name())}
+		for _, field := range oneof.Fields {
+			if name := fieldName(field); schema.Properties.Get(name) != nil {
+				group.Fields = append(group.Fields, name)
+			}
+		}
+		if len(group.Fields) > 1 {
+			schema.Oneofs = append(schema.Oneofs, group)
+		}
+	}
+
+	// One group is a plain oneOf, several must all hold
+	switch len(schema.Oneofs) {
+	case 0:
+	case 1:
+		schema.OneOf = oneofConstraint(schema.Oneofs[0])
+	default:
+		for _, group := range schema.Oneofs {
+			schema.AllOf = append(schema.AllOf, &JSONSchema{OneOf: oneofConstraint(group)})
+		}
+	}
+
+	return schema
+}
+
+// oneofConstraint expresses "at most one of" as a JSON Schema oneOf: exactly
+// one member is present, or none of them is.
+func oneofConstraint(group *OneofGroup) []*JSONSchema {
+	var constraint, members []*JSONSchema
+	for _, field := range group.Fields {
+		members = append(members, &JSONSchema{Required: []string{field}})
+		constraint = append(constraint, &JSONSchema{Required: []string{field}})
+	}
+	return append(constraint, &JSONSchema{Not: &JSONSchema{AnyOf: members}})
+}
+
+// OneofConstraint is a oneof group found at a path of nested message fields.
+type OneofConstraint struct {
+	Path   []string
+	Name   string
+	Fields []string
+}
+
+// CollectOneofs lists the oneof groups of an input schema and of the
+// singular message fields nested in it.
+func (s *JSONSchema) CollectOneofs() []*OneofConstraint {
+	return collectOneofs(s, s.Defs, nil, map[string]bool{})
+}
+
+func collectOneofs(schema *JSONSchema, defs map[string]*JSONSchema, path []string, visiting map[string]bool) []*OneofConstraint {
+	var constraints []*OneofConstraint
+	for _, group := range schema.Oneofs {
+		constraints = append(constraints, &OneofConstraint{Path: path, Name: group.Name, Fields: group.Fields})
+	}
+
+	for _, prop := range schema.Properties {
+		if prop.Schema.Ref == "" || visiting[prop.Schema.RefName()] {
+			continue
+		}
+		name := prop.Schema.RefName()
+		visiting[name] = true
+		nested := append(append([]string{}, path...), prop.Name)
+		constraints = append(constraints, collectOneofs(defs[name], defs, nested, visiting)...)
+		delete(visiting, name)
+	}
+
+	return constraints
+}
+
+// MapPaths lists the map fields of an argument's schema, as dotted paths of
+// argument keys below the argument, "" standing for the argument itself.
+// Maps nested in singular message fields are included, stopping at recursive
+// references as CollectOneofs does.
+func (s *JSONSchema) MapPaths(defs map[string]*JSONSchema) []string {
+	return collectMapPaths(s, defs, "", map[string]bool{})
+}
+
+func collectMapPaths(schema *JSONSchema, defs map[string]*JSONSchema, path string, visiting map[string]bool) []string {
+	if schema.Type == "object" && schema.AdditionalProperties != nil {
+		return []string{path}
+	}
+	if schema.Ref == "" || visiting[schema.RefName()] {
+		return nil
+	}
+
+	name := schema.RefName()
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	var paths []string
+	for _, prop := range defs[name].Properties {
+		nested := prop.Name
+		if path != "" {
+			nested = path + "." + prop.Name
+		}
+		paths = append(paths, collectMapPaths(prop.Schema, defs, nested, visiting)...)
+	}
+	return paths
+}
+
+func (g *schemaGenerator) fieldSchema(field *protogen.Field, output bool, defs map[string]*JSONSchema) *JSONSchema {
+	if field.Desc.IsMap() {
+		return g.mapSchema(field, output, defs)
+	}
+
+	if field.Desc.Cardinality() == protoreflect.Repeated {
+		return &JSONSchema{Type: "array", Items: g.elementSchema(field, output, defs)}
+	}
+
+	return g.elementSchema(field, output, defs)
+}
+
+// elementSchema describes a single value of the field, ignoring repetition.
+func (g *schemaGenerator) elementSchema(field *protogen.Field, output bool, defs map[string]*JSONSchema) *JSONSchema {
+	switch field.Desc.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if schema := wellKnownSchema(field.Message); schema != nil {
+			return schema
+		}
+		return g.messageRef(field.Message, output, defs)
+	case protoreflect.EnumKind:
+		if schema := wellKnownEnumSchema(field.Enum); schema != nil {
+			return schema
+		}
+		return enumSchema(field.Enum)
+	default:
+		return scalarSchema(field.Desc.Kind())
+	}
+}
+
+// mapSchema renders map<K, V> as a JSON object. Keys are always JSON strings,
+// so integer and bool keys are constrained to their string forms.
+func (g *schemaGenerator) mapSchema(field *protogen.Field, output bool, defs map[string]*JSONSchema) *JSONSchema {
+	keyField, valueField := field.Message.Fields[0], field.Message.Fields[1]
+
+	schema := &JSONSchema{
+		Type:                 "object",
+		AdditionalProperties: g.fieldSchema(valueField, output, defs),
+	}
+
+	switch keyField.Desc.Kind() {
+	case protoreflect.BoolKind:
+		schema.PropertyNames = &JSONSchema{Type: "string", Enum: []any{"true", "false"}}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		schema.PropertyNames = &JSONSchema{Type: "string", Pattern: `^[0-9]+$`}
+	case protoreflect.StringKind:
+	default:
+		schema.PropertyNames = &JSONSchema{Type: "string", Pattern: `^-?[0-9]+$`}
+	}
+
+	return schema
+}
+
+// scalarSchema maps a scalar kind to its proto3 JSON encoding: 64-bit
+// integers are decimal strings, bytes are base64 strings and 32-bit
+// integers are bounded JSON numbers.
+func scalarSchema(kind protoreflect.Kind) *JSONSchema {
+	switch kind {
+	case protoreflect.BoolKind:
+		return &JSONSchema{Type: "boolean"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return &JSONSchema{Type: "number"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &JSONSchema{Type: "integer", Format: "int32", Minimum: proto.Int64(math.MinInt32), Maximum: proto.Int64(math.MaxInt32)}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &JSONSchema{Type: "integer", Format: "uint32", Minimum: proto.Int64(0), Maximum: proto.Int64(math.MaxUint32)}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return &JSONSchema{Type: "string", Format: "int64", Pattern: `^-?[0-9]+$`}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &JSONSchema{Type: "string", Format: "uint64", Pattern: `^[0-9]+$`}
+	case protoreflect.BytesKind:
+		return &JSONSchema{Type: "string", Encoding: "base64"}
+	default:
+		return &JSONSchema{Type: "string"}
+	}
+}
+
+// enumSchema lists the enum's value names, which is how protojson encodes
+// them, and documents the values in the description.
+func enumSchema(enum *protogen.Enum) *JSONSchema {
+	schema := &JSONSchema{Type: "string", Description: enumValuesDescription(enum)}
+	for _, value := range enumValues(enum) {
+		schema.Enum = append(schema.Enum, string(value.Desc.Name()))
+	}
+	return schema
+}
+
+// enumValues returns the values agents may choose from, leaving out the
+// zero *_UNSPECIFIED value when skip_unspecified_enums is set.
+func enumValues(enum *protogen.Enum) []*protogen.EnumValue {
+	var values []*protogen.EnumValue
+	for _, value := range enum.Values {
+		if *skipUnspecifiedEnums && value.Desc.Number() == 0 && strings.HasSuffix(string(value.Desc.Name()), "_UNSPECIFIED") {
+			continue
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// enumValuesDescription documents enum values from their leading comments.
+func enumValuesDescription(enum *protogen.Enum) string {
+	var lines []string
+	for _, value := range enumValues(enum) {
+		if comment := strings.TrimSpace(string(value.Comments.Leading)); comment != "" {
+			lines = append(lines, fmt.Sprintf("- %s: %s", value.Desc.Name(), strings.ReplaceAll(comment, "\n", " ")))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "Values:\n" + strings.Join(lines, "\n")
+}
+
+func joinDescriptions(descriptions ...string) string {
+	var parts []string
+	for _, description := range descriptions {
+		if description != "" {
+			parts = append(parts, description)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// messageRef registers the message under "$defs" and returns a reference to it.
+func (g *schemaGenerator) messageRef(message *protogen.Message, output bool, defs map[string]*JSONSchema) *JSONSchema {
+	name := g.defName(message.Desc)
+	if _, ok := defs[name]; !ok {
+		// Reserve the name before descending so recursive fields terminate
+		defs[name] = nil
+		def := g.objectSchema(message, output, defs)
+		if message.Comments.Leading != "" {
+			def.Description = strings.TrimSpace(string(message.Comments.Leading))
+		}
+		defs[name] = def
+	}
+	return &JSONSchema{Ref: "#/$defs/" + name}
+}
+
+// defName picks a short, stable definition name for a message: its name
+// relative to its package, qualified with the package on collisions.
+func (g *schemaGenerator) defName(message protoreflect.MessageDescriptor) string {
+	if name, ok := g.names[message.FullName()]; ok {
+		return name
+	}
+
+	fullName := string(message.FullName())
+	name := strings.TrimPrefix(fullName, string(message.ParentFile().Package())+".")
+	name = strings.ReplaceAll(name, ".", "_")
+	if g.taken[name] {
+		name = strings.ReplaceAll(fullName, ".", "_")
+	}
+
+	g.names[message.FullName()] = name
+	g.taken[name] = true
+	return name
+}
+
+// fieldExamples decodes mcp.v1.field examples, written as JSON. Examples that
+// are not valid JSON are kept as text, and so are string field examples that
+// do not decode to a string, so "abc" and abc both give the string abc.
+func fieldExamples(field *protogen.Field, schema *JSONSchema) []any {
+	var examples []any
+	for _, example := range getFieldOptions(field).GetExamples() {
+		var value any
+		if json.Unmarshal([]byte(example), &value) != nil {
+			value = example
+		} else if _, ok := value.(string); !ok && schema.Type == "string" {
+			value = example
+		}
+		examples = append(examples, value)
+	}
+	return examples
+}
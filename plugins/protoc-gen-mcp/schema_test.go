@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	mcpannotations "proto-to-mcp-tutorial/generated/go/mcp/protobuf"
+)
+
+func TestMessageSchema(t *testing.T) {
+	tests := []struct {
+		message              string
+		skipUnspecifiedEnums bool
+		want                 string
+	}{
+		{
+			message: "Node",
+			want: `{"type":"object","properties":{"name":{"type":"string"},"parent":{"$ref":"#/$defs/Node"},"children":{"type":"array","items":{"$ref":"#/$defs/Node"}}},"required":["name"],` +
+				`"$defs":{"Node":{"type":"object","properties":{"name":{"type":"string"},"parent":{"$ref":"#/$defs/Node"},"children":{"type":"array","items":{"$ref":"#/$defs/Node"}}},"required":["name"]}}}`,
+		},
+		{
+			message: "ImportRequest",
+			want: `{"type":"object","properties":{"book":{"$ref":"#/$defs/Book"},"other_book":{"$ref":"#/$defs/other_Book"}},` +
+				`"$defs":{"Book":{"type":"object","properties":{"title":{"type":"string"}},"required":["title"]},"other_Book":{"type":"object","properties":{"isbn":{"type":"string"}},"required":["isbn"]}}}`,
+		},
+		{
+			message: "EnumRequest",
+			want:    `{"type":"object","properties":{"genre":{"type":"string","enum":["GENRE_UNSPECIFIED","FICTION"]}},"required":["genre"]}`,
+		},
+		{
+			message:              "EnumRequest",
+			skipUnspecifiedEnums: true,
+			want:                 `{"type":"object","properties":{"genre":{"type":"string","enum":["FICTION"]}},"required":["genre"]}`,
+		},
+		{
+			message: "MapRequest",
+			want:    `{"type":"object","properties":{"labels":{"type":"object","additionalProperties":{"type":"string"},"propertyNames":{"type":"string","pattern":"^-?[0-9]+$"}}}}`,
+		},
+		{
+			message: "WrapperRequest",
+			want:    `{"type":"object","properties":{"count":{"anyOf":[{"type":"string","format":"int64","pattern":"^-?[0-9]+$"},{"type":"null"}]}}}`,
+		},
+		{
+			message: "NullRequest",
+			want:    `{"type":"object","properties":{"nothing":{"type":"null"}},"required":["nothing"]}`,
+		},
+		{
+			message: "ScalarRequest",
+			want: `{"type":"object","properties":{"pages":{"type":"integer","format":"int32","minimum":-2147483648,"maximum":2147483647},` +
+				`"size":{"type":"string","format":"uint64","pattern":"^[0-9]+$"},"tags":{"type":"array","items":{"type":"string"}}},"required":["pages","size"]}`,
+		},
+		{
+			message: "OneofRequest",
+			want: `{"type":"object","properties":{"shelf":{"type":"string"},"room":{"type":"string"}},` +
+				`"oneOf":[{"required":["shelf"]},{"required":["room"]},{"not":{"anyOf":[{"required":["shelf"]},{"required":["room"]}]}}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.message, func(t *testing.T) {
+			defer func(skip bool) { *skipUnspecifiedEnums = skip }(*skipUnspecifiedEnums)
+			*skipUnspecifiedEnums = test.skipUnspecifiedEnums
+
+			schema := newSchemaGenerator().messageSchema(testSchemaMessage(t, test.message), false)
+			got, err := json.Marshal(schema)
+			if err != nil {
+				t.Fatalf("json.Marshal failed: %v", err)
+			}
+			if string(got) != test.want {
+				t.Errorf("messageSchema(%s) =\n%s\nwant\n%s", test.message, got, test.want)
+			}
+		})
+	}
+}
+
+func TestPythonType(t *testing.T) {
+	tests := []struct {
+		schema *JSONSchema
+		want   string
+	}{
+		{schema: &JSONSchema{Ref: "#/$defs/Node"}, want: `"NodeType"`},
+		{schema: &JSONSchema{Type: "array", Items: &JSONSchema{Ref: "#/$defs/Node"}}, want: `list["NodeType"]`},
+		{schema: &JSONSchema{Type: "string", Enum: []any{"FICTION"}}, want: `Literal["FICTION"]`},
+		{schema: &JSONSchema{AnyOf: []*JSONSchema{{Type: "boolean"}, {Type: "null"}}}, want: `Optional[bool]`},
+		{schema: &JSONSchema{Type: "null"}, want: `None`},
+		{
+			schema: &JSONSchema{Type: "object", AdditionalProperties: &JSONSchema{Type: "string"}, PropertyNames: &JSONSchema{Type: "string", Pattern: `^-?[0-9]+$`}},
+			want:   `dict[Annotated[str, Field(pattern="^-?[0-9]+$")], str]`,
+		},
+	}
+
+	for _, test := range tests {
+		if got := pythonType(test.schema); got != test.want {
+			t.Errorf("pythonType(%+v) = %s, want %s", test.schema, got, test.want)
+		}
+	}
+}
+
+func TestPythonTypedDict(t *testing.T) {
+	file := testSchemaFile()
+	file.Service = []*descriptorpb.ServiceDescriptorProto{testService("Walk", ".schema.Node", nil, &mcpannotations.MCPToolOptions{})}
+	server := generateTestPythonServer(t, testOtherFile(), testWrappersFile(), testStructFile(), file)
+
+	want := `NodeType = TypedDict("Node", {
+    "name": Required[str],
+    "parent": "NodeType",
+    "children": list["NodeType"],
+}, total=False)`
+	if !strings.Contains(server, want) {
+		t.Errorf("generated server does not define\n%s\ngot\n%s", want, server)
+	}
+}
+
+// testSchemaMessage returns a message of testSchemaFile.
+func testSchemaMessage(t *testing.T, name string) *protogen.Message {
+	t.Helper()
+
+	gen := newTestPlugin(t, testOtherFile(), testWrappersFile(), testStructFile(), testSchemaFile())
+	for _, message := range gen.Files[3].Messages {
+		if string(message.Desc.Name()) == name {
+			return message
+		}
+	}
+	t.Fatalf("no message %s", name)
+	return nil
+}
+
+// testSchemaFile builds schema.proto, with one message per kind of schema:
+//
+//	message Node { string name = 1; Node parent = 2; repeated Node children = 3; }
+//	message Book { string title = 1; }
+//	message ImportRequest { Book book = 1; other.Book other_book = 2; }
+//	enum Genre { GENRE_UNSPECIFIED = 0; FICTION = 1; }
+//	message EnumRequest { Genre genre = 1; }
+//	message MapRequest { map<int32, string> labels = 1; }
+//	message WrapperRequest { google.protobuf.Int64Value count = 1; }
+//	message NullRequest { google.protobuf.NullValue nothing = 1; }
+//	message ScalarRequest { int32 pages = 1; uint64 size = 2; repeated string tags = 3; }
+//	message OneofRequest { oneof destination { string shelf = 1; string room = 2; } }
+func testSchemaFile() *descriptorpb.FileDescriptorProto {
+	mapRequest := testMessage("MapRequest", testField("labels", 1, repeated, messageType, ".schema.MapRequest.LabelsEntry"))
+	mapRequest.NestedType = []*descriptorpb.DescriptorProto{{
+		Name: proto.String("LabelsEntry"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			testField("key", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+			testField("value", 2, optional, stringType, ""),
+		},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	}}
+
+	shelf := testField("shelf", 1, optional, stringType, "")
+	shelf.OneofIndex = proto.Int32(0)
+	room := testField("room", 2, optional, stringType, "")
+	room.OneofIndex = proto.Int32(0)
+	oneofRequest := testMessage("OneofRequest", shelf, room)
+	oneofRequest.OneofDecl = []*descriptorpb.OneofDescriptorProto{{Name: proto.String("destination")}}
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("schema.proto"),
+		Package:    proto.String("schema"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"other.proto", "google/protobuf/wrappers.proto", "google/protobuf/struct.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/schema")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			testMessage("Node",
+				testField("name", 1, optional, stringType, ""),
+				testField("parent", 2, optional, messageType, ".schema.Node"),
+				testField("children", 3, repeated, messageType, ".schema.Node")),
+			testMessage("Book", testField("title", 1, optional, stringType, "")),
+			testMessage("ImportRequest",
+				testField("book", 1, optional, messageType, ".schema.Book"),
+				testField("other_book", 2, optional, messageType, ".other.Book")),
+			testMessage("EnumRequest", testField("genre", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".schema.Genre")),
+			mapRequest,
+			testMessage("WrapperRequest", testField("count", 1, optional, messageType, ".google.protobuf.Int64Value")),
+			testMessage("NullRequest", testField("nothing", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".google.protobuf.NullValue")),
+			testMessage("ScalarRequest",
+				testField("pages", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+				testField("size", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_UINT64, ""),
+				testField("tags", 3, repeated, stringType, "")),
+			oneofRequest,
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Genre"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("GENRE_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("FICTION"), Number: proto.Int32(1)},
+			},
+		}},
+	}
+}
+
+// testOtherFile builds other.proto, whose Book has the same name as the
+// Book of schema.proto:
+//
+//	message Book { string isbn = 1; }
+func testOtherFile() *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("other.proto"),
+		Package: proto.String("other"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/other")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name:  proto.String("Book"),
+			Field: []*descriptorpb.FieldDescriptorProto{testField("isbn", 1, optional, stringType, "")},
+		}},
+	}
+}
+
+func testWrappersFile() *descriptorpb.FileDescriptorProto {
+	return protodesc.ToFileDescriptorProto(wrapperspb.File_google_protobuf_wrappers_proto)
+}
+
+func testStructFile() *descriptorpb.FileDescriptorProto {
+	return protodesc.ToFileDescriptorProto(structpb.File_google_protobuf_struct_proto)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// renderTemplates executes a templated target. By default the built-in
+// template named tmpl.Name() renders to defaultName (or out_file). With the
+// template option, the user's templates are parsed alongside the built-in
+// ones, which they may include or redefine, and render instead:
+//
+//   - a template file renders to defaultName (or out_file);
+//   - in a directory, every NAME.tmpl renders to a file called NAME, and
+//     files starting with "_" only define templates for the others.
+func renderTemplates(gen *protogen.Plugin, server *MCPServer, tmpl *template.Template, defaultName string) error {
+	outputs := map[string]string{outputFileName(defaultName): tmpl.Name()}
+	if *templatePath != "" {
+		var err error
+		if outputs, err = parseUserTemplates(tmpl, *templatePath, defaultName); err != nil {
+			return err
+		}
+	}
+
+	var names []string
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, outputs[name], server); err != nil {
+			return fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		gen.NewGeneratedFile(name, ".").P(buf.String())
+	}
+	return nil
+}
+
+// parseUserTemplates parses the template file or directory at path into
+// tmpl and returns the template to execute for each output file.
+func parseUserTemplates(tmpl *template.Template, path, defaultName string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	if !info.IsDir() {
+		if err := parseTemplateFile(tmpl, path); err != nil {
+			return nil, err
+		}
+		return map[string]string{outputFileName(defaultName): filepath.Base(path)}, nil
+	}
+
+	if *outFile != "" {
+		return nil, errors.New("out_file cannot be combined with a template directory, whose file names give the outputs")
+	}
+	files, err := filepath.Glob(filepath.Join(path, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	outputs := map[string]string{}
+	for _, file := range files {
+		if err := parseTemplateFile(tmpl, file); err != nil {
+			return nil, err
+		}
+		if name := filepath.Base(file); !strings.HasPrefix(name, "_") {
+			outputs[strings.TrimSuffix(name, ".tmpl")] = name
+		}
+	}
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("invalid template: no *.tmpl files to render in %s", path)
+	}
+	return outputs, nil
+}
+
+// parseTemplateFile adds a template file to tmpl under its base name.
+func parseTemplateFile(tmpl *template.Template, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	if _, err := tmpl.New(filepath.Base(path)).Parse(string(content)); err != nil {
+		return fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	return nil
+}
+
+// splitWords breaks an identifier into lower case words at underscores,
+// hyphens, dots, spaces and case changes: "getBookID" and "get_book_id"
+// both give get, book, id.
+func splitWords(text string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(text)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(previous) || nextLower {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, unicode.ToLower(r))
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+func snakeCase(text string) string {
+	return strings.Join(splitWords(text), "_")
+}
+
+func kebabCase(text string) string {
+	return strings.Join(splitWords(text), "-")
+}
+
+func pascalCase(text string) string {
+	words := splitWords(text)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, "")
+}
+
+func camelCase(text string) string {
+	pascal := pascalCase(text)
+	if pascal == "" {
+		return pascal
+	}
+	return strings.ToLower(pascal[:1]) + pascal[1:]
+}
+
+// toJSON renders a value, typically a *JSONSchema, as compact JSON.
+func toJSON(value any) (string, error) {
+	return encodeJSON(value, "")
+}
+
+// toPrettyJSON renders a value as JSON indented by two spaces.
+func toPrettyJSON(value any) (string, error) {
+	return encodeJSON(value, "  ")
+}
+
+func encodeJSON(value any, indent string) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// schemaTypeName summarizes a schema's type for documentation: "string",
+// "integer", a $defs name such as "Book", "array<Book>", "map<string, Book>"
+// or a union such as "string | null".
+func schemaTypeName(schema *JSONSchema) string {
+	switch {
+	case schema == nil:
+		return "any"
+	case schema.Ref != "":
+		return schema.RefName()
+	case len(schema.AnyOf) > 0:
+		members := make([]string, len(schema.AnyOf))
+		for i, member := range schema.AnyOf {
+			members[i] = schemaTypeName(member)
+		}
+		return strings.Join(members, " | ")
+	case schema.Type == "array":
+		return "array<" + schemaTypeName(schema.Items) + ">"
+	case schema.Type == "object" && schema.AdditionalProperties != nil:
+		return "map<string, " + schemaTypeName(schema.AdditionalProperties) + ">"
+	case schema.Type == "":
+		return "any"
+	default:
+		return schema.Type
+	}
+}
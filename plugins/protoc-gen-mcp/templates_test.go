@@ -0,0 +1,157 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string // Template files to write
+		template string            // The template option, relative to the files
+		outFile  string
+		want     map[string]string // Output files by name, with text they contain
+		absent   string            // Text no output may contain
+		err      string
+	}{
+		{
+			name:     "file",
+			files:    map[string]string{"server.tmpl": "# {{.Name}}"},
+			template: "server.tmpl",
+			want:     map[string]string{"mcp_server.py": "# rich.v1"},
+		},
+		{
+			name:     "file with out_file",
+			files:    map[string]string{"server.tmpl": "# {{.Name}}"},
+			template: "server.tmpl",
+			outFile:  "rich.py",
+			want:     map[string]string{"rich.py": "# rich.v1"},
+		},
+		{
+			name: "directory",
+			files: map[string]string{
+				"README.md.tmpl": `{{template "_header.tmpl" .}} tools`,
+				"tools.txt.tmpl": "{{range .Methods}}{{.ToolName}}{{end}}",
+				"_header.tmpl":   "# {{.Name}}",
+			},
+			template: ".",
+			want:     map[string]string{"README.md": "# rich.v1 tools", "tools.txt": "rich_move_it"},
+		},
+		{
+			name:     "directory with out_file",
+			files:    map[string]string{"README.md.tmpl": "{{.Name}}"},
+			template: ".",
+			outFile:  "rich.py",
+			err:      "out_file cannot be combined with a template directory",
+		},
+		{
+			name:     "directory of partials",
+			files:    map[string]string{"_header.tmpl": "{{.Name}}"},
+			template: ".",
+			err:      "no *.tmpl files to render",
+		},
+		{
+			name:     "include built-in",
+			files:    map[string]string{"server.tmpl": `# Custom header{{"\n"}}{{template "mcp_server" .}}`},
+			template: "server.tmpl",
+			want:     map[string]string{"mcp_server.py": "# Custom header\n#!/usr/bin/env python3"},
+		},
+		{
+			name:     "redefine built-in",
+			files:    map[string]string{"server.tmpl": `{{define "http_request"}}_result = {"custom": True}{{end}}{{template "mcp_server" .}}`},
+			template: "server.tmpl",
+			want:     map[string]string{"mcp_server.py": `        _result = {"custom": True}`},
+			absent:   "await make_api_request(",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func(path, out string) { *templatePath, *outFile = path, out }(*templatePath, *outFile)
+
+			dir := t.TempDir()
+			for name, content := range test.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			*templatePath = filepath.Join(dir, test.template)
+			*outFile = test.outFile
+
+			gen := newTestPlugin(t, testRichFile())
+			methods, err := extractMCPMethods(gen)
+			if err != nil {
+				t.Fatalf("extractMCPMethods failed: %v", err)
+			}
+			server, err := extractMCPServer(gen, methods)
+			if err != nil {
+				t.Fatalf("extractMCPServer failed: %v", err)
+			}
+
+			err = generateMCPServer(gen, server)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("generateMCPServer() error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generateMCPServer failed: %v", err)
+			}
+
+			got := map[string]string{}
+			for _, file := range gen.Response().File {
+				got[file.GetName()] = file.GetContent()
+			}
+			if len(got) != len(test.want) {
+				t.Errorf("outputs = %q, want %d files", got, len(test.want))
+			}
+			for name, want := range test.want {
+				if !strings.Contains(got[name], want) {
+					t.Errorf("%s = %q, want it to contain %q", name, got[name], want)
+				}
+				if test.absent != "" && strings.Contains(got[name], test.absent) {
+					t.Errorf("%s contains %q", name, test.absent)
+				}
+			}
+		})
+	}
+}
+
+func TestCaseHelpers(t *testing.T) {
+	tests := []struct {
+		text                        string
+		words                       []string
+		snake, camel, pascal, kebab string
+	}{
+		{text: "HTTPServerID", words: []string{"http", "server", "id"}, snake: "http_server_id", camel: "httpServerId", pascal: "HttpServerId", kebab: "http-server-id"},
+		{text: "get_book", words: []string{"get", "book"}, snake: "get_book", camel: "getBook", pascal: "GetBook", kebab: "get-book"},
+		{text: "getBookID", words: []string{"get", "book", "id"}, snake: "get_book_id", camel: "getBookId", pascal: "GetBookId", kebab: "get-book-id"},
+		{text: "v2.list-books", words: []string{"v2", "list", "books"}, snake: "v2_list_books", camel: "v2ListBooks", pascal: "V2ListBooks", kebab: "v2-list-books"},
+		{text: "", snake: "", camel: "", pascal: "", kebab: ""},
+	}
+
+	for _, test := range tests {
+		if got := splitWords(test.text); !reflect.DeepEqual(got, test.words) {
+			t.Errorf("splitWords(%q) = %q, want %q", test.text, got, test.words)
+		}
+		for _, helper := range []struct {
+			name string
+			fn   func(string) string
+			want string
+		}{
+			{"snakeCase", snakeCase, test.snake},
+			{"camelCase", camelCase, test.camel},
+			{"pascalCase", pascalCase, test.pascal},
+			{"kebabCase", kebabCase, test.kebab},
+		} {
+			if got := helper.fn(test.text); got != helper.want {
+				t.Errorf("%s(%q) = %q, want %q", helper.name, test.text, got, helper.want)
+			}
+		}
+	}
+}
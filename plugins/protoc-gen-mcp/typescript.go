@@ -0,0 +1,549 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// generateTypeScriptServer writes mcp_server.ts, a Node MCP server built on
+// the official TypeScript SDK. Its zod schemas come from the same JSON
+// Schemas as the Python target, and requests are built the same way.
+func generateTypeScriptServer(gen *protogen.Plugin, server *MCPServer) error {
+	for _, method := range server.Methods {
+		if method.Backend == "grpc" {
+			return fmt.Errorf("%s: the typescript target does not support the grpc backend", method.Method.Desc.FullName())
+		}
+	}
+
+	var tmpl *template.Template
+	funcMap := templateFuncs()
+	for name, fn := range map[string]any{
+		"tsQuote":       tsString,
+		"zodShape":      zodShape,
+		"zodParam":      zodParam,
+		"tsPath":        tsPath,
+		"tsCondition":   tsCondition,
+		"tsDescription": tsDescription,
+		"oneofs":        tsOneofGroups,
+		"comment": func(text string) string {
+			return strings.ReplaceAll(text, "*/", `*\/`)
+		},
+		"lines": func(text string) []string {
+			return strings.Split(text, "\n")
+		},
+		"request": func(method *MCPMethod, binding *HTTPInfo) (string, error) {
+			var buf bytes.Buffer
+			data := struct {
+				Method  *MCPMethod
+				Binding *HTTPInfo
+			}{method, binding}
+			err := tmpl.ExecuteTemplate(&buf, "http_request", data)
+			return buf.String(), err
+		},
+	} {
+		funcMap[name] = fn
+	}
+
+	tmpl = template.Must(template.New("mcp_server").Funcs(funcMap).Parse(tsServerTemplate))
+	template.Must(tmpl.New("http_request").Parse(tsRequestTemplate))
+
+	return renderTemplates(gen, server, tmpl, "mcp_server.ts")
+}
+
+// tsString renders a JavaScript string literal.
+func tsString(text string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(text)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// tsLiteral renders a JSON value as a JavaScript literal.
+func tsLiteral(value any) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// tsSchemaName returns the constant holding a message definition's schema.
+// The suffix keeps messages such as Date or Map from shadowing globals.
+func tsSchemaName(name string) string {
+	return name + "Schema"
+}
+
+// zodType renders the zod schema for a JSON Schema, mirroring pythonType.
+// Message references are lazy so definitions can refer to each other, and
+// themselves, in any order.
+func zodType(schema *JSONSchema) string {
+	expr := zodBaseType(schema)
+	if schema.Description != "" {
+		expr += ".describe(" + tsString(schema.Description) + ")"
+	}
+	return expr
+}
+
+func zodBaseType(schema *JSONSchema) string {
+	if schema.Ref != "" {
+		return "z.lazy(() => " + tsSchemaName(schema.RefName()) + ")"
+	}
+
+	if len(schema.AnyOf) > 0 {
+		var members []string
+		nullable := false
+		for _, member := range schema.AnyOf {
+			if member.Type == "null" {
+				nullable = true
+				continue
+			}
+			members = append(members, zodType(member))
+		}
+		expr := members[0]
+		if len(members) > 1 {
+			expr = "z.union([" + strings.Join(members, ", ") + "])"
+		}
+		if nullable {
+			expr += ".nullable()"
+		}
+		return expr
+	}
+
+	if len(schema.Enum) > 0 {
+		return "z.enum(" + tsLiteral(schema.Enum) + ")"
+	}
+
+	switch schema.Type {
+	case "string":
+		expr := "z.string()"
+		if schema.Format == "date-time" {
+			expr += ".datetime({ offset: true })"
+		}
+		if schema.Pattern != "" {
+			expr += ".regex(new RegExp(" + tsString(schema.Pattern) + "))"
+		}
+		return expr
+	case "integer":
+		expr := "z.number().int()"
+		if schema.Minimum != nil {
+			expr += fmt.Sprintf(".min(%d)", *schema.Minimum)
+		}
+		if schema.Maximum != nil {
+			expr += fmt.Sprintf(".max(%d)", *schema.Maximum)
+		}
+		return expr
+	case "number":
+		return "z.number()"
+	case "boolean":
+		return "z.boolean()"
+	case "null":
+		return "z.null()"
+	case "array":
+		if schema.Items != nil {
+			return "z.array(" + zodType(schema.Items) + ")"
+		}
+		return "z.array(z.any())"
+	case "object":
+		if schema.AdditionalProperties != nil {
+			keyType := "z.string()"
+			if schema.PropertyNames != nil {
+				keyType = zodType(schema.PropertyNames)
+			}
+			return "z.record(" + keyType + ", " + zodType(schema.AdditionalProperties) + ")"
+		}
+		if len(schema.Properties) > 0 {
+			// Inline objects such as google.protobuf.Any keep their declared keys
+			return "z.object(" + zodShape(schema, "") + ").passthrough()"
+		}
+		return "z.record(z.string(), z.any())"
+	default:
+		return "z.any()"
+	}
+}
+
+// zodShape renders an object schema's properties as a zod raw shape, one
+// property per line at the given indentation.
+func zodShape(schema *JSONSchema, indent string) string {
+	if len(schema.Properties) == 0 {
+		return "{}"
+	}
+
+	var lines []string
+	for _, prop := range schema.Properties {
+		expr := zodType(prop.Schema)
+		if !schema.IsRequired(prop.Name) {
+			expr += ".optional()"
+		}
+		lines = append(lines, indent+"  "+tsString(prop.Name)+": "+expr+",")
+	}
+	return "{\n" + strings.Join(lines, "\n") + "\n" + indent + "}"
+}
+
+// zodParam renders a tool parameter's zod schema.
+func zodParam(param *MCPParameter) string {
+	if param.Required {
+		return zodType(param.Schema)
+	}
+	return zodType(param.Schema) + ".optional()"
+}
+
+// tsPath renders a path template as a TypeScript expression that expands
+// its variables from the tool arguments.
+func tsPath(template *PathTemplate) string {
+	var parts []string
+	literal := ""
+	for _, segment := range template.Segments {
+		literal += "/"
+		if segment.Variable == nil {
+			literal += segment.Literal
+			continue
+		}
+
+		parts = append(parts, tsString(literal))
+		literal = ""
+
+		variable := segment.Variable
+		pattern := "undefined"
+		if variable.Pattern() != "" {
+			pattern = tsString(variable.Pattern())
+		}
+		parts = append(parts, fmt.Sprintf("pathValue(%s, %s, %s, %t)",
+			tsString(strings.Join(variable.Names, ".")), tsPathVariable(variable), pattern, variable.MultiSegment()))
+	}
+	if template.Verb != "" {
+		literal += ":" + template.Verb
+	}
+	if literal != "" {
+		parts = append(parts, tsString(literal))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// tsPathVariable renders the expression reading a path variable's value.
+func tsPathVariable(variable *PathVariable) string {
+	if len(variable.Names) == 1 {
+		return "args." + variable.Names[0]
+	}
+	return fmt.Sprintf("nestedValue(args.%s, %s)", variable.Names[0], tsLiteral(variable.Names[1:]))
+}
+
+// tsCondition renders the check that a binding's path variables are set.
+func tsCondition(binding *HTTPInfo) string {
+	var conditions []string
+	for _, variable := range binding.Template.Variables() {
+		conditions = append(conditions, tsPathVariable(variable)+" != null")
+	}
+	return strings.Join(conditions, " && ")
+}
+
+// tsOneofGroups renders oneof constraints as the [path, name, members] list
+// consumed by findOneofConflicts.
+func tsOneofGroups(oneofs []*OneofConstraint) string {
+	groups := []any{}
+	for _, oneof := range oneofs {
+		path := append([]string{}, oneof.Path...)
+		fields := append([]string{}, oneof.Fields...)
+		groups = append(groups, []any{path, oneof.Name, fields})
+	}
+	return tsLiteral(groups)
+}
+
+// tsDescription renders a tool's description. Parameters are described in
+// the zod schema, so only the bindings, the oneof groups a zod shape cannot
+// express and the returned fields are added here, worded as in Python.
+func tsDescription(method *MCPMethod) string {
+	description := method.Description
+	if len(method.Bindings) > 0 {
+		var lines []string
+		for _, binding := range method.Bindings {
+			lines = append(lines, "HTTP: "+binding.Method+" "+binding.Path)
+		}
+		description += "\n\n" + strings.Join(lines, "\n")
+	}
+	if len(method.Oneofs) > 0 {
+		lines := []string{"Mutually exclusive parameters (set at most one of each group):"}
+		for _, oneof := range method.Oneofs {
+			name := strings.Join(append(append([]string{}, oneof.Path...), oneof.Name), ".")
+			lines = append(lines, "- "+name+": "+strings.Join(oneof.Fields, ", "))
+		}
+		description += "\n\n" + strings.Join(lines, "\n")
+	}
+	if len(method.Outputs) > 0 {
+		description += "\n\nReturns:\n" + formatFieldList(method.Outputs)
+	}
+	return description
+}
+
+// tsRequestTemplate renders the statements that call one HTTP binding and
+// store the decoded response in result, exactly as the Python target does.
+const tsRequestTemplate = `// Construct the URL
+const url = {{if .Method.BaseURL}}{{tsQuote .Method.BaseURL}}{{else}}API_BASE{{end}} + {{tsPath .Binding.Template}};
+
+// Prepare the request body{{if eq .Binding.Body "*"}}
+const payload: Record<string, unknown> = {};{{range .Binding.BodyParams}}
+{{if .Required}}payload[{{tsQuote .Name}}] = args.{{.Name}};{{else}}if (args.{{.Name}} != null) {
+  payload[{{tsQuote .Name}}] = args.{{.Name}};
+}{{end}}{{end}}{{else}}
+const payload: unknown = {{with .Binding.BodyParameter}}args.{{.Name}}{{else}}undefined{{end}};{{end}}
+
+// Encode the remaining fields as query parameters
+const params: [string, string][] = [];{{range .Binding.QueryParams}}
+addQueryParam(params, {{tsQuote .Name}}, args.{{.Name}}{{with $.Method.MapPaths .}}, {{toJSON .}}{{end}});{{end}}
+
+// Make the API request
+result = await makeApiRequest(url, {{tsQuote .Binding.Method}}, hasContent(payload) ? payload : undefined, params{{if .Binding.ResponseBody}}, {{tsQuote .Binding.ResponseBody}}{{end}});`
+
+const tsServerTemplate = `#!/usr/bin/env node
+/**
+ * {{comment .Name}} - MCP server auto-generated from Protocol Buffers
+ *
+{{- if .Instructions}}
+{{- range lines (comment .Instructions)}}
+ *{{if .}} {{.}}{{end}}
+{{- end}}
+{{- else}}
+ * This server provides access to {{range $i, $service := .Services}}{{if $i}}, {{end}}{{$service.Desc.Name}}{{end}} operations
+ * through the Model Context Protocol.
+{{- end}}
+ *
+ * Requires the @modelcontextprotocol/sdk{{if not .VerifyTLS}}, undici{{end}} and zod packages.
+ */
+
+import { McpServer } from "@modelcontextprotocol/sdk/server/mcp.js";
+import { StdioServerTransport } from "@modelcontextprotocol/sdk/server/stdio.js";
+{{- if not .VerifyTLS}}
+import { Agent } from "undici";
+{{- end}}
+import { z } from "zod";
+
+const API_BASE = {{tsQuote .APIBase}};
+{{- if not .VerifyTLS}}
+
+// Accept self-signed certificates of the API, as the Python target does.
+// Only API requests use this agent; the rest of the process still verifies.
+const API_DISPATCHER = new Agent({ connect: { rejectUnauthorized: false } });
+{{- end}}
+
+// Initialize the MCP server
+const server = new McpServer(
+  { name: {{tsQuote .Name}}, version: {{tsQuote .Version}} },{{if .Instructions}}
+  { instructions: {{tsQuote .Instructions}} },{{end}}
+);
+
+/** Make a HTTP request, wrapping a response_body field back under its key. */
+async function makeApiRequest(
+  url: string,
+  method: string,
+  payload?: unknown,
+  params: [string, string][] = [],
+  responseBody?: string,
+): Promise<unknown> {
+  const query = new URLSearchParams(params).toString();
+  try {
+    const response = await fetch(query ? url + "?" + query : url, {
+      method,
+      headers: { "Content-Type": "application/json" },
+      body: payload === undefined ? undefined : JSON.stringify(payload),
+      signal: AbortSignal.timeout(30000),{{if not .VerifyTLS}}
+      // Node's fetch is undici, which takes the agent as its dispatcher
+      dispatcher: API_DISPATCHER,{{end}}
+    }{{if not .VerifyTLS}} as RequestInit{{end}});
+    const text = await response.text();
+    if (!response.ok) {
+      return { error: "HTTP " + response.status + ": " + text };
+    }
+
+    // HEAD and OPTIONS answer with headers rather than a body
+    const verb = method.toUpperCase();
+    if ((verb === "HEAD" || verb === "OPTIONS") && !text) {
+      return { status_code: response.status, headers: Object.fromEntries(response.headers) };
+    }
+
+    // Handle DELETE responses that might be empty
+    if (verb === "DELETE" && (response.status === 200 || response.status === 204)) {
+      return { success: true, message: "Resource deleted successfully" };
+    }
+
+    let result: unknown;
+    try {
+      result = JSON.parse(text);
+    } catch {
+      return { success: true };
+    }
+    return responseBody ? { [responseBody]: result } : result;
+  } catch (e) {
+    return { error: String(e) };
+  }
+}
+
+/** Whether a payload is worth sending, following Python truthiness. */
+function hasContent(value: unknown): boolean {
+  if (Array.isArray(value)) {
+    return value.length > 0;
+  }
+  if (value !== null && typeof value === "object") {
+    return Object.keys(value).length > 0;
+  }
+  return Boolean(value);
+}
+
+/** Describe oneof groups that have more than one member set. */
+function findOneofConflicts(args: Record<string, unknown>, groups: [string[], string, string[]][]): string[] {
+  const conflicts: string[] = [];
+  for (const [path, name, members] of groups) {
+    const value = nestedValue(args, path);
+    if (value === null || typeof value !== "object" || Array.isArray(value)) {
+      continue;
+    }
+    const message = value as Record<string, unknown>;
+    const present = members.filter((member) => message[member] != null);
+    if (present.length > 1) {
+      const prefix = path.map((key) => key + ".").join("");
+      conflicts.push(
+        "Only one of " + members.map((m) => prefix + m).join(", ") + " may be set for oneof '" + name +
+          "', got " + present.map((m) => prefix + m).join(", "),
+      );
+    }
+  }
+  return conflicts;
+}
+
+/**
+ * Expand a path template variable. Single-segment variables percent-escape
+ * every reserved character including "/", multi-segment ones keep "/" as
+ * the separator.
+ */
+function pathValue(name: string, value: unknown, pattern?: string, multiSegment = false): string {
+  if (value == null) {
+    throw new Error("missing value for path parameter " + name);
+  }
+  const text = String(value);
+  if (pattern && !new RegExp(pattern).test(text)) {
+    throw new Error(name + " must match the pattern " + pattern + ", got " + JSON.stringify(text));
+  }
+  const escape = (part: string) =>
+    encodeURIComponent(part).replace(/[!'()*]/g, (c) => "%" + c.charCodeAt(0).toString(16).toUpperCase());
+  return multiSegment ? text.split("/").map(escape).join("/") : escape(text);
+}
+
+/** Read a nested field of a message argument, undefined if any level is unset. */
+function nestedValue(value: unknown, keys: string[]): unknown {
+  for (const key of keys) {
+    if (value === null || typeof value !== "object" || Array.isArray(value)) {
+      return undefined;
+    }
+    value = (value as Record<string, unknown>)[key];
+  }
+  return value;
+}
+
+/**
+ * Encode a value as grpc-gateway query parameters. Nested messages become
+ * dotted paths (a.b=c), repeated fields repeat the key and booleans use
+ * their JSON spelling. Map fields, listed in maps by their path below the
+ * argument ("" for the argument itself), become a[key]=value.
+ */
+function addQueryParam(params: [string, string][], name: string, value: unknown, maps: string[] = [], path = ""): void {
+  if (value == null) {
+    return;
+  }
+  if (Array.isArray(value)) {
+    for (const item of value) {
+      addQueryParam(params, name, item, maps, path);
+    }
+  } else if (typeof value === "object" && maps.includes(path)) {
+    for (const [key, item] of Object.entries(value)) {
+      addQueryParam(params, name + "[" + key + "]", item);
+    }
+  } else if (typeof value === "object") {
+    for (const [key, item] of Object.entries(value)) {
+      addQueryParam(params, name + "." + key, item, maps, path ? path + "." + key : key);
+    }
+  } else {
+    params.push([name, String(value)]);
+  }
+}
+
+// Message types
+{{range .Types}}
+const {{.Name}}Schema: z.ZodTypeAny = z.object({{zodShape .Schema ""}}){{with .Schema.Description}}.describe({{tsQuote .}}){{end}};
+{{end}}
+// MCP Tools
+{{range .Methods}}{{$method := .}}
+server.registerTool(
+  {{tsQuote .ToolName}},
+  {
+{{- if .Title}}
+    title: {{tsQuote .Title}},{{end}}
+    description: {{tsQuote (tsDescription .)}},
+    inputSchema: {{"{"}}{{range .Parameters}}
+      {{tsQuote .Name}}: {{zodParam .}},{{end}}
+    {{"}"}},{{with .Annotations.Hints}}
+    annotations: { {{- range $i, $hint := .}}{{if $i}},{{end}} {{$hint.Name}}: {{$hint.Value}}{{end}} },{{end}}
+  },
+  async (args) => {
+    try {
+{{- if .Oneofs}}
+      // Reject arguments that set more than one member of a oneof
+      const conflicts = findOneofConflicts(args, {{oneofs .Oneofs}});
+      if (conflicts.length > 0) {
+        return textResult({ error: conflicts.join("; "), tool_name: {{tsQuote .ToolName}} });
+      }
+{{end}}
+      let result: unknown;
+{{- if .Bindings}}{{if gt (len .Bindings) 1}}
+
+      // Use the most specific HTTP binding whose path parameters are all set{{end}}
+{{- range bindingChoices .Bindings}}
+{{- if eq .Keyword "if"}}
+      if ({{tsCondition .Binding}}) {
+{{indent (request $method .Binding) 8}}
+{{- else if eq .Keyword "elif"}}
+      } else if ({{tsCondition .Binding}}) {
+{{indent (request $method .Binding) 8}}
+{{- else if eq .Keyword "else"}}
+      } else {
+{{if .Binding}}{{indent (request $method .Binding) 8}}{{else}}        result = { error: "No HTTP binding matches the supplied arguments" };{{end}}
+      }
+{{- else}}
+
+{{indent (request $method .Binding) 6}}
+{{- end}}
+{{- end}}
+{{- else}}
+      result = { error: "No HTTP endpoint defined for this method" };
+{{- end}}
+
+      // Return formatted JSON response
+      return textResult(result);
+    } catch (e) {
+      // Handle any errors that occur during execution
+      return textResult({
+        error: "Tool execution failed: " + String(e),
+        tool_name: {{tsQuote .ToolName}},
+        error_type: e instanceof Error ? e.name : typeof e,
+      });
+    }
+  },
+);
+{{end}}
+/** Wrap a JSON value as the tool's text content. */
+function textResult(value: unknown) {
+  return { content: [{ type: "text" as const, text: JSON.stringify(value, null, 2) }] };
+}
+
+async function main() {
+  // Run the MCP server over stdio
+  await server.connect(new StdioServerTransport());
+}
+
+main().catch((error) => {
+  console.error(error);
+  process.exit(1);
+});`
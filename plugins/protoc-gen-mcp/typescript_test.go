@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"os/exec"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestTSDescriptionOneofs(t *testing.T) {
+	method := &MCPMethod{
+		Description: "Move a book.",
+		Oneofs: []*OneofConstraint{
+			{Name: "destination", Fields: []string{"shelf", "room"}},
+			{Path: []string{"book"}, Name: "source", Fields: []string{"isbn", "book_id"}},
+		},
+	}
+
+	want := "Move a book.\n\n" +
+		"Mutually exclusive parameters (set at most one of each group):\n" +
+		"- destination: shelf, room\n" +
+		"- book.source: isbn, book_id"
+	if got := tsDescription(method); got != want {
+		t.Errorf("tsDescription() = %q, want %q", got, want)
+	}
+}
+
+// tsAddQueryParam matches addQueryParam in the TypeScript template. Only its
+// signature has type annotations, so with a plain signature node can run it.
+var tsAddQueryParam = regexp.MustCompile(`(?ms)^function addQueryParam\(.*?\): void \{$(.*?)^\}$`)
+
+func TestTSAddQueryParam(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+
+	match := tsAddQueryParam.FindStringSubmatch(tsServerTemplate)
+	if match == nil {
+		t.Fatal("addQueryParam not found in the TypeScript template")
+	}
+	// The generated tools pass each argument's map paths, as the Python test does
+	script := `function addQueryParam(params, name, value, maps = [], path = "") {` + match[1] + `}
+const args = ` + testQueryArguments + `;
+const maps = {book: ["labels"], filters: [""]};
+const params = [];
+for (const [name, value] of Object.entries(args)) {
+  addQueryParam(params, name, value, maps[name] || []);
+}
+console.log(JSON.stringify(params));
+`
+	output, err := exec.Command(node, "-e", script).CombinedOutput()
+	if err != nil {
+		t.Fatalf("running addQueryParam failed: %v\n%s", err, output)
+	}
+
+	var got [][]string
+	if err := json.Unmarshal(output, &got); err != nil {
+		t.Fatalf("addQueryParam output is not JSON: %s", output)
+	}
+	if !reflect.DeepEqual(got, testQueryParams) {
+		t.Errorf("params = %q, want %q", got, testQueryParams)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// wellKnownSchema returns the canonical proto3 JSON form of a
+// google.protobuf well-known type, or nil for any other message.
+func wellKnownSchema(message *protogen.Message) *JSONSchema {
+	if message.Desc.ParentFile().Package() != "google.protobuf" {
+		return nil
+	}
+
+	switch message.Desc.Name() {
+	case "Timestamp":
+		return &JSONSchema{
+			Type:        "string",
+			Format:      "date-time",
+			Description: `RFC 3339 timestamp, e.g. "2024-01-15T10:30:00Z"`,
+		}
+	case "Duration":
+		return &JSONSchema{
+			Type:        "string",
+			Pattern:     `^-?[0-9]+(\.[0-9]{1,9})?s$`,
+			Description: `Duration in seconds with an "s" suffix, e.g. "1.5s"`,
+		}
+	case "FieldMask":
+		return &JSONSchema{
+			Type:        "string",
+			Description: `Comma-separated lowerCamelCase field paths, e.g. "title,author"`,
+		}
+	case "Struct":
+		return &JSONSchema{Type: "object"}
+	case "Value":
+		// Any JSON value
+		return &JSONSchema{}
+	case "ListValue":
+		return &JSONSchema{Type: "array"}
+	case "Empty":
+		return &JSONSchema{Type: "object"}
+	case "Any":
+		return &JSONSchema{
+			Type: "object",
+			Properties: Properties{{
+				Name: "@type",
+				Schema: &JSONSchema{
+					Type:        "string",
+					Description: `Type URL of the packed message, e.g. "type.googleapis.com/google.protobuf.Duration"`,
+				},
+			}},
+			Required: []string{"@type"},
+		}
+	case "DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value",
+		"UInt32Value", "BoolValue", "StringValue", "BytesValue":
+		// Wrappers encode as their bare value, with null for an unset wrapper
+		value := message.Desc.Fields().ByName("value")
+		return &JSONSchema{AnyOf: []*JSONSchema{scalarSchema(value.Kind()), {Type: "null"}}}
+	}
+
+	return nil
+}
+
+// wellKnownEnumSchema returns the JSON form of google.protobuf.NullValue,
+// which protojson writes as null rather than by value name, or nil for any
+// other enum.
+func wellKnownEnumSchema(enum *protogen.Enum) *JSONSchema {
+	if enum.Desc.FullName() == "google.protobuf.NullValue" {
+		return &JSONSchema{Type: "null"}
+	}
+	return nil
+}
+
+// isWellKnownType reports whether the message has a canonical JSON mapping
+// and so is not described field by field.
+func isWellKnownType(message *protogen.Message) bool {
+	return message != nil && wellKnownSchema(message) != nil
+}
+
+// wellKnownTypeName returns the short JSON type of a well-known type for docstrings.
+func wellKnownTypeName(message *protogen.Message) string {
+	schema := wellKnownSchema(message)
+	switch {
+	case schema.Type != "":
+		return schema.Type
+	case len(schema.AnyOf) > 0:
+		return schema.AnyOf[0].Type
+	default:
+		return "any"
+	}
+}